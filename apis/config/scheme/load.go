@@ -0,0 +1,31 @@
+package scheme
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// LoadEmissionsArgs reads EmissionsArgs, as given to the scheduler in the
+// plugin config, from a file.
+func LoadEmissionsArgs(path string) (*config.EmissionsArgs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := runtime.Decode(Codecs.UniversalDecoder(), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	args, ok := obj.(*config.EmissionsArgs)
+	if !ok {
+		return nil, fmt.Errorf("want %s to be of kind EmissionsArgs, got %T", path, obj)
+	}
+
+	return args, nil
+}
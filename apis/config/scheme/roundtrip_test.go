@@ -0,0 +1,26 @@
+package scheme
+
+import (
+	"math/rand"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/apitesting/roundtrip"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// fuzzSeed seeds the fuzzer, so that failures can be reproduced.
+const fuzzSeed = 1
+
+// TestEmissionsArgsRoundTrip fuzzes internal EmissionsArgs and round trips
+// them through every external version: v1alpha1, served as
+// kubescheduler.config.k8s.io/v1, and v1beta1.
+func TestEmissionsArgsRoundTrip(t *testing.T) {
+	f := fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(fuzzSeed), Codecs)
+
+	gvk := config.SchemeGroupVersion.WithKind("EmissionsArgs")
+
+	roundtrip.RoundTripSpecificKindWithoutProtobuf(t, gvk, Scheme, Codecs, f, nil)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/siderolabs/kube-scheduler/apis/config"
 	"github.com/siderolabs/kube-scheduler/apis/config/v1alpha1"
+	"github.com/siderolabs/kube-scheduler/apis/config/v1beta1"
 )
 
 var (
@@ -26,4 +27,5 @@ func init() {
 func AddToScheme(scheme *runtime.Scheme) {
 	utilruntime.Must(config.AddToScheme(scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
 }
@@ -1,6 +1,9 @@
 package config
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -8,10 +11,166 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 type EmissionsArgs struct {
 	metav1.TypeMeta
 
+	// Provider is the source of the emissions index.
+	Provider string
+
 	// WattTimeUsername is the WattTime username.
 	WattTimeUsername string
 	// WattTimePassword is the WattTime password.
 	WattTimePassword string
 	// WattTimeBA is the WattTime BA.
 	WattTimeBA string
+	// CredentialsSecretRef references the Secret holding the provider
+	// credentials, instead of WattTimeUsername and WattTimePassword.
+	CredentialsSecretRef *v1.SecretReference
+	// CredentialsFile is the path of a file holding the provider credentials,
+	// instead of WattTimeUsername and WattTimePassword.
+	CredentialsFile string
+
+	// NodeIdleDuration is how long a node must have no workload pods
+	// assigned before it is considered idle.
+	NodeIdleDuration metav1.Duration
+	// IdleIgnoredNamespaces lists namespaces whose pods do not count
+	// towards a node being busy.
+	IdleIgnoredNamespaces []string
+	// SchedulerNames lists the schedulers whose pending pods power nodes on,
+	// in addition to the profiles running the Emissions plugin. Pending pods
+	// of every scheduler count if both are empty.
+	SchedulerNames []string
+	// WakeOnLANBroadcastAddress is the UDP address Wake-on-LAN magic
+	// packets are sent to.
+	WakeOnLANBroadcastAddress string
+	// TalosConfigSecretRef references the Secret holding the talosconfig
+	// used to shut down Talos nodes.
+	TalosConfigSecretRef *v1.SecretReference
+
+	// MinNodeUptime is how long a node stays powered on before it may be
+	// powered off again.
+	MinNodeUptime metav1.Duration
+	// MinNodeDowntime is how long a node stays powered off before it may be
+	// powered on again.
+	MinNodeDowntime metav1.Duration
+	// IndexHysteresis widens the gap between the index at which nodes are
+	// powered on and the index at which they are powered off.
+	IndexHysteresis int32
+	// MaxPowerCyclesPerDay limits how many times a node is powered off per
+	// day. Zero means no limit.
+	MaxPowerCyclesPerDay int32
+
+	// NodePoolLabel is the node label whose value names the pool a node
+	// belongs to.
+	NodePoolLabel string
+	// NodePools sets the minimum number of online nodes per pool.
+	NodePools []NodePool
+
+	// MaxEvictionsPerMinute limits evictions across the cluster. Zero means
+	// no limit.
+	MaxEvictionsPerMinute int32
+	// MaxNamespaceEvictionsPerMinute limits evictions per namespace. Zero
+	// means no limit.
+	MaxNamespaceEvictionsPerMinute int32
+	// MaxEvictedReplicasPercent is the largest share of a workload's
+	// replicas that may be evicted at once. At least one replica can always
+	// be evicted. Zero means no limit.
+	MaxEvictedReplicasPercent int32
+
+	// IndexRefreshInterval is how often the index is fetched from the
+	// provider.
+	IndexRefreshInterval metav1.Duration
+
+	// EvictionConfirmationPeriod is how long the index has to stay above a
+	// pod's priority before the pod is evicted.
+	EvictionConfirmationPeriod metav1.Duration
+	// EvictionConfirmationSamples is how many consecutive index samples
+	// have to be above a pod's priority before the pod is evicted.
+	EvictionConfirmationSamples int32
+	// EvictionGracePeriodSeconds overrides the termination grace period of
+	// evicted pods.
+	EvictionGracePeriodSeconds *int64
+
+	// SuspendJobs suspends the Jobs of pods that would be evicted instead of
+	// evicting them, and suspends CronJobs to skip their runs, resuming both
+	// once the index drops.
+	SuspendJobs bool
+	// ScaleWorkloads scales Deployments and StatefulSets annotated with
+	// carbon.siderolabs.com/scale-down-replicas down instead of evicting their
+	// pods, restoring their replicas once the index drops.
+	ScaleWorkloads bool
+
+	// MinCandidateNodesPercentage and MinCandidateNodesAbsolute bound the
+	// number of nodes preemption looks for victims on, as the DefaultPreemption
+	// arguments of the same names, which do not apply while the plugin
+	// preempts in its place.
+	MinCandidateNodesPercentage int32
+	MinCandidateNodesAbsolute   int32
+
+	// DeferralMode selects where pods are held while the index is above their
+	// priority: PreFilter keeps them in the scheduling queue, Permit holds them
+	// on the node they were placed on.
+	DeferralMode string
+	// MaxWaitPolicy is applied to pods held at Permit once their
+	// carbon.siderolabs.com/max-wait has passed: Allow binds them anyway,
+	// Reject releases their node and keeps them in the scheduling queue
+	// until the index drops.
+	MaxWaitPolicy string
+
+	// FailurePolicy is applied to pods while the index is unavailable: Defer
+	// keeps them pending, Allow schedules them as if the index were low.
+	FailurePolicy string
+
+	// DisableControllers leaves pod eviction and node power management to a
+	// separately deployed carbon-controller.
+	DisableControllers bool
+
+	// DynamicConfigMapRef references a ConfigMap holding EmissionsArgs whose
+	// tunable settings replace these at runtime.
+	DynamicConfigMapRef *ConfigMapReference
+}
+
+// Values of EmissionsArgs.Provider.
+const (
+	ProviderWattTime = "WattTime"
+)
+
+// Values of EmissionsArgs.DeferralMode.
+const (
+	DeferralModePreFilter = "PreFilter"
+	DeferralModePermit    = "Permit"
+)
+
+// Values of EmissionsArgs.MaxWaitPolicy.
+const (
+	MaxWaitPolicyAllow  = "Allow"
+	MaxWaitPolicyReject = "Reject"
+)
+
+// Values of EmissionsArgs.FailurePolicy.
+const (
+	FailurePolicyDefer = "Defer"
+	FailurePolicyAllow = "Allow"
+)
+
+// DefaultWakeOnLANBroadcastAddress is the default WakeOnLANBroadcastAddress.
+const DefaultWakeOnLANBroadcastAddress = "255.255.255.255:9"
+
+// Defaults of MinCandidateNodesPercentage and MinCandidateNodesAbsolute, the
+// same as DefaultPreemption's.
+const (
+	DefaultMinCandidateNodesPercentage = 10
+	DefaultMinCandidateNodesAbsolute   = 100
+)
+
+// ConfigMapReference references a ConfigMap.
+type ConfigMapReference struct {
+	Namespace string
+	Name      string
+}
+
+// NodePool defines the always-on capacity of a pool of nodes.
+type NodePool struct {
+	// Name is the value of NodePoolLabel identifying the pool.
+	Name string
+	// MinOnlineNodes is the number of healthy nodes in the pool that are
+	// never powered off.
+	MinOnlineNodes int32
 }
@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// SetDefaults_EmissionsArgs sets the default parameters for the Emissions
+// plugin.
+func SetDefaults_EmissionsArgs(obj *EmissionsArgs) {
+	if obj.Provider == nil {
+		obj.Provider = pointer.String(config.ProviderWattTime)
+	}
+
+	if obj.IndexRefreshInterval == nil {
+		obj.IndexRefreshInterval = &metav1.Duration{Duration: 5 * time.Minute}
+	}
+
+	if obj.FailurePolicy == nil {
+		obj.FailurePolicy = pointer.String(config.FailurePolicyDefer)
+	}
+
+	if obj.DeferralMode == nil {
+		obj.DeferralMode = pointer.String(config.DeferralModePreFilter)
+	}
+
+	if obj.MaxWaitPolicy == nil {
+		obj.MaxWaitPolicy = pointer.String(config.MaxWaitPolicyAllow)
+	}
+
+	if obj.NodeIdleDuration == nil {
+		obj.NodeIdleDuration = &metav1.Duration{Duration: 10 * time.Minute}
+	}
+
+	if obj.WakeOnLANBroadcastAddress == nil {
+		obj.WakeOnLANBroadcastAddress = pointer.String(config.DefaultWakeOnLANBroadcastAddress)
+	}
+
+	if obj.MinCandidateNodesPercentage == nil {
+		obj.MinCandidateNodesPercentage = pointer.Int32(config.DefaultMinCandidateNodesPercentage)
+	}
+
+	if obj.MinCandidateNodesAbsolute == nil {
+		obj.MinCandidateNodesAbsolute = pointer.Int32(config.DefaultMinCandidateNodesAbsolute)
+	}
+}
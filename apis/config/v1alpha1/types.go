@@ -1,6 +1,9 @@
 package v1alpha1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:defaulter-gen=true
@@ -9,10 +12,151 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 type EmissionsArgs struct {
 	metav1.TypeMeta `json:",inline"`
 
+	// Provider is the source of the emissions index. Defaults to WattTime,
+	// the only provider.
+	Provider *string `json:"provider,omitempty"`
+
 	// WattTimeUsername is the WattTime username.
 	WattTimeUsername *string `json:"wattTimeUsername,omitempty"`
 	// WattTimePassword is the WattTime password.
 	WattTimePassword *string `json:"wattTimePassword,omitempty"`
 	// WattTimeBA is the WattTime BA.
 	WattTimeBA *string `json:"wattTimeBA,omitempty"`
+	// CredentialsSecretRef references the Secret holding the provider
+	// credentials under the "username" and "password" keys, instead of
+	// wattTimeUsername and wattTimePassword. The Secret is read again every
+	// minute, logging in again when the credentials change.
+	CredentialsSecretRef *v1.SecretReference `json:"credentialsSecretRef,omitempty"`
+	// CredentialsFile is the path of a YAML file holding the provider
+	// credentials as "username" and "password", instead of wattTimeUsername
+	// and wattTimePassword, such as a key of a mounted Secret. The file is
+	// watched and read again when it changes.
+	CredentialsFile *string `json:"credentialsFile,omitempty"`
+
+	// NodeIdleDuration is how long a node must have no workload pods
+	// assigned before it is considered idle.
+	NodeIdleDuration *metav1.Duration `json:"nodeIdleDuration,omitempty"`
+	// IdleIgnoredNamespaces lists namespaces whose pods do not count
+	// towards a node being busy.
+	IdleIgnoredNamespaces []string `json:"idleIgnoredNamespaces,omitempty"`
+	// SchedulerNames lists the schedulers whose pending pods power nodes on,
+	// in addition to the profiles running the Emissions plugin. Pending pods
+	// of every scheduler count if both are empty.
+	SchedulerNames []string `json:"schedulerNames,omitempty"`
+	// WakeOnLANBroadcastAddress is the UDP address Wake-on-LAN magic
+	// packets are sent to. Defaults to 255.255.255.255:9.
+	WakeOnLANBroadcastAddress *string `json:"wakeOnLANBroadcastAddress,omitempty"`
+	// TalosConfigSecretRef references the Secret holding the talosconfig
+	// used to shut down Talos nodes. The talosconfig is read from the
+	// "talosconfig" key.
+	TalosConfigSecretRef *v1.SecretReference `json:"talosConfigSecretRef,omitempty"`
+
+	// MinNodeUptime is how long a node stays powered on before it may be
+	// powered off again.
+	MinNodeUptime *metav1.Duration `json:"minNodeUptime,omitempty"`
+	// MinNodeDowntime is how long a node stays powered off before it may be
+	// powered on again.
+	MinNodeDowntime *metav1.Duration `json:"minNodeDowntime,omitempty"`
+	// IndexHysteresis widens the gap between the index at which nodes are
+	// powered on and the index at which they are powered off: nodes are
+	// powered on only for pending pods with priority > index + hysteresis,
+	// and kept on while pending pods have priority > index - hysteresis.
+	IndexHysteresis *int32 `json:"indexHysteresis,omitempty"`
+	// MaxPowerCyclesPerDay limits how many times a node is powered off per
+	// day. Zero means no limit.
+	MaxPowerCyclesPerDay *int32 `json:"maxPowerCyclesPerDay,omitempty"`
+
+	// NodePoolLabel is the node label whose value names the pool a node
+	// belongs to.
+	NodePoolLabel *string `json:"nodePoolLabel,omitempty"`
+	// NodePools sets the minimum number of online nodes per pool.
+	NodePools []NodePool `json:"nodePools,omitempty"`
+
+	// MaxEvictionsPerMinute limits evictions across the cluster. Zero means
+	// no limit.
+	MaxEvictionsPerMinute *int32 `json:"maxEvictionsPerMinute,omitempty"`
+	// MaxNamespaceEvictionsPerMinute limits evictions per namespace. Zero
+	// means no limit.
+	MaxNamespaceEvictionsPerMinute *int32 `json:"maxNamespaceEvictionsPerMinute,omitempty"`
+	// MaxEvictedReplicasPercent is the largest share of a workload's
+	// replicas that may be evicted at once. At least one replica can always
+	// be evicted. Zero means no limit.
+	MaxEvictedReplicasPercent *int32 `json:"maxEvictedReplicasPercent,omitempty"`
+
+	// IndexRefreshInterval is how often the index is fetched from the
+	// provider.
+	IndexRefreshInterval *metav1.Duration `json:"indexRefreshInterval,omitempty"`
+
+	// EvictionConfirmationPeriod is how long the index has to stay above a
+	// pod's priority before the pod is evicted.
+	EvictionConfirmationPeriod *metav1.Duration `json:"evictionConfirmationPeriod,omitempty"`
+	// EvictionConfirmationSamples is how many consecutive index samples
+	// have to be above a pod's priority before the pod is evicted.
+	EvictionConfirmationSamples *int32 `json:"evictionConfirmationSamples,omitempty"`
+	// EvictionGracePeriodSeconds overrides the termination grace period of
+	// evicted pods. Unset uses each pod's own grace period.
+	EvictionGracePeriodSeconds *int64 `json:"evictionGracePeriodSeconds,omitempty"`
+
+	// SuspendJobs suspends the Jobs of pods that would be evicted instead of
+	// evicting them, and suspends CronJobs to skip their runs, resuming both
+	// once the index drops.
+	SuspendJobs *bool `json:"suspendJobs,omitempty"`
+	// ScaleWorkloads scales Deployments and StatefulSets annotated with
+	// carbon.siderolabs.com/scale-down-replicas down instead of evicting their
+	// pods, restoring their replicas once the index drops.
+	ScaleWorkloads *bool `json:"scaleWorkloads,omitempty"`
+
+	// MinCandidateNodesPercentage is the minimum share of nodes preemption
+	// looks for victims on, as the DefaultPreemption argument of the same
+	// name, which does not apply while the plugin preempts in its place.
+	// Defaults to 10.
+	MinCandidateNodesPercentage *int32 `json:"minCandidateNodesPercentage,omitempty"`
+	// MinCandidateNodesAbsolute is the minimum number of nodes preemption
+	// looks for victims on, as the DefaultPreemption argument of the same
+	// name. Defaults to 100.
+	MinCandidateNodesAbsolute *int32 `json:"minCandidateNodesAbsolute,omitempty"`
+
+	// DeferralMode selects where pods are held while the index is above their
+	// priority: PreFilter keeps them in the scheduling queue, Permit holds them
+	// on the node they were placed on.
+	DeferralMode *string `json:"deferralMode,omitempty"`
+	// MaxWaitPolicy is applied to pods held at Permit once their
+	// carbon.siderolabs.com/max-wait has passed: Allow binds them anyway,
+	// Reject releases their node and keeps them in the scheduling queue
+	// until the index drops.
+	MaxWaitPolicy *string `json:"maxWaitPolicy,omitempty"`
+
+	// FailurePolicy is applied to pods while the index is unavailable: Defer
+	// keeps them pending, Allow schedules them as if the index were low.
+	// Defaults to Defer.
+	FailurePolicy *string `json:"failurePolicy,omitempty"`
+
+	// DisableControllers leaves pod eviction and node power management to a
+	// separately deployed carbon-controller.
+	DisableControllers *bool `json:"disableControllers,omitempty"`
+
+	// DynamicConfigMapRef references a ConfigMap whose "config" key holds
+	// EmissionsArgs, of any version, applied at runtime without a restart.
+	// Only the tunable settings are taken from it, those missing keeping their
+	// startup values; the others, such as the provider and its credentials,
+	// only change on restart. The startup settings are restored when the
+	// ConfigMap is deleted.
+	DynamicConfigMapRef *ConfigMapReference `json:"dynamicConfigMapRef,omitempty"`
+}
+
+// ConfigMapReference references a ConfigMap.
+type ConfigMapReference struct {
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+}
+
+// NodePool defines the always-on capacity of a pool of nodes.
+type NodePool struct {
+	// Name is the value of NodePoolLabel identifying the pool.
+	Name string `json:"name"`
+	// MinOnlineNodes is the number of healthy nodes in the pool that are
+	// never powered off.
+	MinOnlineNodes int32 `json:"minOnlineNodes"`
 }
@@ -6,7 +6,10 @@
 package v1alpha1
 
 import (
+	unsafe "unsafe"
+
 	config "github.com/siderolabs/kube-scheduler/apis/config"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -19,6 +22,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ConfigMapReference)(nil), (*config.ConfigMapReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConfigMapReference_To_config_ConfigMapReference(a.(*ConfigMapReference), b.(*config.ConfigMapReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ConfigMapReference)(nil), (*ConfigMapReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ConfigMapReference_To_v1alpha1_ConfigMapReference(a.(*config.ConfigMapReference), b.(*ConfigMapReference), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EmissionsArgs)(nil), (*config.EmissionsArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_EmissionsArgs_To_config_EmissionsArgs(a.(*EmissionsArgs), b.(*config.EmissionsArgs), scope)
 	}); err != nil {
@@ -29,9 +42,41 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodePool)(nil), (*config.NodePool)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodePool_To_config_NodePool(a.(*NodePool), b.(*config.NodePool), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodePool)(nil), (*NodePool)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodePool_To_v1alpha1_NodePool(a.(*config.NodePool), b.(*NodePool), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_ConfigMapReference_To_config_ConfigMapReference(in *ConfigMapReference, out *config.ConfigMapReference, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Name = in.Name
 	return nil
 }
 
+// Convert_v1alpha1_ConfigMapReference_To_config_ConfigMapReference is an autogenerated conversion function.
+func Convert_v1alpha1_ConfigMapReference_To_config_ConfigMapReference(in *ConfigMapReference, out *config.ConfigMapReference, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConfigMapReference_To_config_ConfigMapReference(in, out, s)
+}
+
+func autoConvert_config_ConfigMapReference_To_v1alpha1_ConfigMapReference(in *config.ConfigMapReference, out *ConfigMapReference, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_config_ConfigMapReference_To_v1alpha1_ConfigMapReference is an autogenerated conversion function.
+func Convert_config_ConfigMapReference_To_v1alpha1_ConfigMapReference(in *config.ConfigMapReference, out *ConfigMapReference, s conversion.Scope) error {
+	return autoConvert_config_ConfigMapReference_To_v1alpha1_ConfigMapReference(in, out, s)
+}
+
 func autoConvert_v1alpha1_EmissionsArgs_To_config_EmissionsArgs(in *EmissionsArgs, out *config.EmissionsArgs, s conversion.Scope) error {
 	if err := v1.Convert_Pointer_string_To_string(&in.WattTimeUsername, &out.WattTimeUsername, s); err != nil {
 		return err
@@ -42,6 +87,82 @@ func autoConvert_v1alpha1_EmissionsArgs_To_config_EmissionsArgs(in *EmissionsArg
 	if err := v1.Convert_Pointer_string_To_string(&in.WattTimeBA, &out.WattTimeBA, s); err != nil {
 		return err
 	}
+	out.CredentialsSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.CredentialsSecretRef))
+	if err := v1.Convert_Pointer_string_To_string(&in.CredentialsFile, &out.CredentialsFile, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.NodeIdleDuration, &out.NodeIdleDuration, s); err != nil {
+		return err
+	}
+	out.IdleIgnoredNamespaces = *(*[]string)(unsafe.Pointer(&in.IdleIgnoredNamespaces))
+	out.SchedulerNames = *(*[]string)(unsafe.Pointer(&in.SchedulerNames))
+	if err := v1.Convert_Pointer_string_To_string(&in.WakeOnLANBroadcastAddress, &out.WakeOnLANBroadcastAddress, s); err != nil {
+		return err
+	}
+	out.TalosConfigSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.TalosConfigSecretRef))
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.MinNodeUptime, &out.MinNodeUptime, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.MinNodeDowntime, &out.MinNodeDowntime, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.IndexHysteresis, &out.IndexHysteresis, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxPowerCyclesPerDay, &out.MaxPowerCyclesPerDay, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.NodePoolLabel, &out.NodePoolLabel, s); err != nil {
+		return err
+	}
+	out.NodePools = *(*[]config.NodePool)(unsafe.Pointer(&in.NodePools))
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxEvictionsPerMinute, &out.MaxEvictionsPerMinute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxNamespaceEvictionsPerMinute, &out.MaxNamespaceEvictionsPerMinute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxEvictedReplicasPercent, &out.MaxEvictedReplicasPercent, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.IndexRefreshInterval, &out.IndexRefreshInterval, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.EvictionConfirmationPeriod, &out.EvictionConfirmationPeriod, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.EvictionConfirmationSamples, &out.EvictionConfirmationSamples, s); err != nil {
+		return err
+	}
+	out.EvictionGracePeriodSeconds = (*int64)(unsafe.Pointer(in.EvictionGracePeriodSeconds))
+	if err := v1.Convert_Pointer_bool_To_bool(&in.SuspendJobs, &out.SuspendJobs, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.ScaleWorkloads, &out.ScaleWorkloads, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.DeferralMode, &out.DeferralMode, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.MaxWaitPolicy, &out.MaxWaitPolicy, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.DisableControllers, &out.DisableControllers, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.Provider, &out.Provider, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_string_To_string(&in.FailurePolicy, &out.FailurePolicy, s); err != nil {
+		return err
+	}
+	out.DynamicConfigMapRef = (*config.ConfigMapReference)(unsafe.Pointer(in.DynamicConfigMapRef))
 	return nil
 }
 
@@ -60,6 +181,82 @@ func autoConvert_config_EmissionsArgs_To_v1alpha1_EmissionsArgs(in *config.Emiss
 	if err := v1.Convert_string_To_Pointer_string(&in.WattTimeBA, &out.WattTimeBA, s); err != nil {
 		return err
 	}
+	out.CredentialsSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.CredentialsSecretRef))
+	if err := v1.Convert_string_To_Pointer_string(&in.CredentialsFile, &out.CredentialsFile, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.NodeIdleDuration, &out.NodeIdleDuration, s); err != nil {
+		return err
+	}
+	out.IdleIgnoredNamespaces = *(*[]string)(unsafe.Pointer(&in.IdleIgnoredNamespaces))
+	out.SchedulerNames = *(*[]string)(unsafe.Pointer(&in.SchedulerNames))
+	if err := v1.Convert_string_To_Pointer_string(&in.WakeOnLANBroadcastAddress, &out.WakeOnLANBroadcastAddress, s); err != nil {
+		return err
+	}
+	out.TalosConfigSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.TalosConfigSecretRef))
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.MinNodeUptime, &out.MinNodeUptime, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.MinNodeDowntime, &out.MinNodeDowntime, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.IndexHysteresis, &out.IndexHysteresis, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxPowerCyclesPerDay, &out.MaxPowerCyclesPerDay, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.NodePoolLabel, &out.NodePoolLabel, s); err != nil {
+		return err
+	}
+	out.NodePools = *(*[]NodePool)(unsafe.Pointer(&in.NodePools))
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxEvictionsPerMinute, &out.MaxEvictionsPerMinute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxNamespaceEvictionsPerMinute, &out.MaxNamespaceEvictionsPerMinute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxEvictedReplicasPercent, &out.MaxEvictedReplicasPercent, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.IndexRefreshInterval, &out.IndexRefreshInterval, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.EvictionConfirmationPeriod, &out.EvictionConfirmationPeriod, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.EvictionConfirmationSamples, &out.EvictionConfirmationSamples, s); err != nil {
+		return err
+	}
+	out.EvictionGracePeriodSeconds = (*int64)(unsafe.Pointer(in.EvictionGracePeriodSeconds))
+	if err := v1.Convert_bool_To_Pointer_bool(&in.SuspendJobs, &out.SuspendJobs, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.ScaleWorkloads, &out.ScaleWorkloads, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.DeferralMode, &out.DeferralMode, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.MaxWaitPolicy, &out.MaxWaitPolicy, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.DisableControllers, &out.DisableControllers, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.Provider, &out.Provider, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_string_To_Pointer_string(&in.FailurePolicy, &out.FailurePolicy, s); err != nil {
+		return err
+	}
+	out.DynamicConfigMapRef = (*ConfigMapReference)(unsafe.Pointer(in.DynamicConfigMapRef))
 	return nil
 }
 
@@ -67,3 +264,25 @@ func autoConvert_config_EmissionsArgs_To_v1alpha1_EmissionsArgs(in *config.Emiss
 func Convert_config_EmissionsArgs_To_v1alpha1_EmissionsArgs(in *config.EmissionsArgs, out *EmissionsArgs, s conversion.Scope) error {
 	return autoConvert_config_EmissionsArgs_To_v1alpha1_EmissionsArgs(in, out, s)
 }
+
+func autoConvert_v1alpha1_NodePool_To_config_NodePool(in *NodePool, out *config.NodePool, s conversion.Scope) error {
+	out.Name = in.Name
+	out.MinOnlineNodes = in.MinOnlineNodes
+	return nil
+}
+
+// Convert_v1alpha1_NodePool_To_config_NodePool is an autogenerated conversion function.
+func Convert_v1alpha1_NodePool_To_config_NodePool(in *NodePool, out *config.NodePool, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodePool_To_config_NodePool(in, out, s)
+}
+
+func autoConvert_config_NodePool_To_v1alpha1_NodePool(in *config.NodePool, out *NodePool, s conversion.Scope) error {
+	out.Name = in.Name
+	out.MinOnlineNodes = in.MinOnlineNodes
+	return nil
+}
+
+// Convert_config_NodePool_To_v1alpha1_NodePool is an autogenerated conversion function.
+func Convert_config_NodePool_To_v1alpha1_NodePool(in *config.NodePool, out *NodePool, s conversion.Scope) error {
+	return autoConvert_config_NodePool_To_v1alpha1_NodePool(in, out, s)
+}
@@ -6,9 +6,27 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmissionsArgs) DeepCopyInto(out *EmissionsArgs) {
 	*out = *in
@@ -28,6 +46,156 @@ func (in *EmissionsArgs) DeepCopyInto(out *EmissionsArgs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.CredentialsFile != nil {
+		in, out := &in.CredentialsFile, &out.CredentialsFile
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeIdleDuration != nil {
+		in, out := &in.NodeIdleDuration, &out.NodeIdleDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.IdleIgnoredNamespaces != nil {
+		in, out := &in.IdleIgnoredNamespaces, &out.IdleIgnoredNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchedulerNames != nil {
+		in, out := &in.SchedulerNames, &out.SchedulerNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WakeOnLANBroadcastAddress != nil {
+		in, out := &in.WakeOnLANBroadcastAddress, &out.WakeOnLANBroadcastAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.TalosConfigSecretRef != nil {
+		in, out := &in.TalosConfigSecretRef, &out.TalosConfigSecretRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.MinNodeUptime != nil {
+		in, out := &in.MinNodeUptime, &out.MinNodeUptime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinNodeDowntime != nil {
+		in, out := &in.MinNodeDowntime, &out.MinNodeDowntime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.IndexHysteresis != nil {
+		in, out := &in.IndexHysteresis, &out.IndexHysteresis
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPowerCyclesPerDay != nil {
+		in, out := &in.MaxPowerCyclesPerDay, &out.MaxPowerCyclesPerDay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodePoolLabel != nil {
+		in, out := &in.NodePoolLabel, &out.NodePoolLabel
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxEvictionsPerMinute != nil {
+		in, out := &in.MaxEvictionsPerMinute, &out.MaxEvictionsPerMinute
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNamespaceEvictionsPerMinute != nil {
+		in, out := &in.MaxNamespaceEvictionsPerMinute, &out.MaxNamespaceEvictionsPerMinute
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxEvictedReplicasPercent != nil {
+		in, out := &in.MaxEvictedReplicasPercent, &out.MaxEvictedReplicasPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IndexRefreshInterval != nil {
+		in, out := &in.IndexRefreshInterval, &out.IndexRefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EvictionConfirmationPeriod != nil {
+		in, out := &in.EvictionConfirmationPeriod, &out.EvictionConfirmationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EvictionConfirmationSamples != nil {
+		in, out := &in.EvictionConfirmationSamples, &out.EvictionConfirmationSamples
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EvictionGracePeriodSeconds != nil {
+		in, out := &in.EvictionGracePeriodSeconds, &out.EvictionGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuspendJobs != nil {
+		in, out := &in.SuspendJobs, &out.SuspendJobs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScaleWorkloads != nil {
+		in, out := &in.ScaleWorkloads, &out.ScaleWorkloads
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinCandidateNodesPercentage != nil {
+		in, out := &in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinCandidateNodesAbsolute != nil {
+		in, out := &in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeferralMode != nil {
+		in, out := &in.DeferralMode, &out.DeferralMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxWaitPolicy != nil {
+		in, out := &in.MaxWaitPolicy, &out.MaxWaitPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.DisableControllers != nil {
+		in, out := &in.DisableControllers, &out.DisableControllers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.DynamicConfigMapRef != nil {
+		in, out := &in.DynamicConfigMapRef, &out.DynamicConfigMapRef
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
 	return
 }
 
@@ -48,3 +216,19 @@ func (in *EmissionsArgs) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -13,5 +13,10 @@ import (
 // Public to allow building arbitrary schemes.
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&EmissionsArgs{}, func(obj interface{}) { SetObjectDefaults_EmissionsArgs(obj.(*EmissionsArgs)) })
 	return nil
 }
+
+func SetObjectDefaults_EmissionsArgs(in *EmissionsArgs) {
+	SetDefaults_EmissionsArgs(in)
+}
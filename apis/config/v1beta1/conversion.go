@@ -0,0 +1,314 @@
+package v1beta1
+
+import (
+	"fmt"
+	"unsafe"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// Convert_v1beta1_EmissionsArgs_To_config_EmissionsArgs flattens the blocks
+// into the internal type. Unset blocks convert as if they were empty.
+func Convert_v1beta1_EmissionsArgs_To_config_EmissionsArgs(in *EmissionsArgs, out *config.EmissionsArgs, s conversion.Scope) error {
+	if err := autoConvert_v1beta1_EmissionsArgs_To_config_EmissionsArgs(in, out, s); err != nil {
+		return err
+	}
+
+	provider := in.Provider
+	if provider == nil {
+		provider = &Provider{}
+	}
+
+	if err := v1.Convert_Pointer_string_To_string(&provider.Name, &out.Provider, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&provider.RefreshInterval, &out.IndexRefreshInterval, s); err != nil {
+		return err
+	}
+
+	wattTime := provider.WattTime
+	if wattTime == nil {
+		wattTime = &WattTime{}
+	}
+
+	if err := v1.Convert_Pointer_string_To_string(&wattTime.Username, &out.WattTimeUsername, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_string_To_string(&wattTime.Password, &out.WattTimePassword, s); err != nil {
+		return err
+	}
+
+	out.CredentialsSecretRef = (*corev1.SecretReference)(unsafe.Pointer(wattTime.CredentialsSecretRef))
+
+	if err := v1.Convert_Pointer_string_To_string(&wattTime.CredentialsFile, &out.CredentialsFile, s); err != nil {
+		return err
+	}
+
+	switch len(in.Regions) {
+	case 0:
+		out.WattTimeBA = ""
+	case 1:
+		out.WattTimeBA = in.Regions[0].Name
+	default:
+		return fmt.Errorf("only one region is supported, got %d", len(in.Regions))
+	}
+
+	policies := in.Policies
+	if policies == nil {
+		policies = &Policies{}
+	}
+
+	if err := v1.Convert_Pointer_string_To_string(&policies.DeferralMode, &out.DeferralMode, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_string_To_string(&policies.MaxWaitPolicy, &out.MaxWaitPolicy, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_string_To_string(&policies.FailurePolicy, &out.FailurePolicy, s); err != nil {
+		return err
+	}
+
+	if err := convertPowerManagementToInternal(in.PowerManagement, out, s); err != nil {
+		return err
+	}
+
+	if err := convertEvictionToInternal(in.Eviction, out, s); err != nil {
+		return err
+	}
+
+	preemption := in.Preemption
+	if preemption == nil {
+		preemption = &Preemption{}
+	}
+
+	if err := v1.Convert_Pointer_int32_To_int32(&preemption.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
+		return err
+	}
+
+	return v1.Convert_Pointer_int32_To_int32(&preemption.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute, s)
+}
+
+func convertPowerManagementToInternal(in *PowerManagement, out *config.EmissionsArgs, s conversion.Scope) error {
+	if in == nil {
+		in = &PowerManagement{}
+	}
+
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.NodeIdleDuration, &out.NodeIdleDuration, s); err != nil {
+		return err
+	}
+
+	out.IdleIgnoredNamespaces = *(*[]string)(unsafe.Pointer(&in.IdleIgnoredNamespaces))
+	out.SchedulerNames = *(*[]string)(unsafe.Pointer(&in.SchedulerNames))
+
+	if err := v1.Convert_Pointer_string_To_string(&in.WakeOnLANBroadcastAddress, &out.WakeOnLANBroadcastAddress, s); err != nil {
+		return err
+	}
+
+	out.TalosConfigSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.TalosConfigSecretRef))
+
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.MinNodeUptime, &out.MinNodeUptime, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.MinNodeDowntime, &out.MinNodeDowntime, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_int32_To_int32(&in.IndexHysteresis, &out.IndexHysteresis, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxPowerCyclesPerDay, &out.MaxPowerCyclesPerDay, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_string_To_string(&in.NodePoolLabel, &out.NodePoolLabel, s); err != nil {
+		return err
+	}
+
+	out.NodePools = *(*[]config.NodePool)(unsafe.Pointer(&in.NodePools))
+
+	return nil
+}
+
+func convertEvictionToInternal(in *Eviction, out *config.EmissionsArgs, s conversion.Scope) error {
+	if in == nil {
+		in = &Eviction{}
+	}
+
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxEvictionsPerMinute, &out.MaxEvictionsPerMinute, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxNamespaceEvictionsPerMinute, &out.MaxNamespaceEvictionsPerMinute, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxEvictedReplicasPercent, &out.MaxEvictedReplicasPercent, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.ConfirmationPeriod, &out.EvictionConfirmationPeriod, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_Pointer_int32_To_int32(&in.ConfirmationSamples, &out.EvictionConfirmationSamples, s); err != nil {
+		return err
+	}
+
+	out.EvictionGracePeriodSeconds = (*int64)(unsafe.Pointer(in.GracePeriodSeconds))
+
+	if err := v1.Convert_Pointer_bool_To_bool(&in.SuspendJobs, &out.SuspendJobs, s); err != nil {
+		return err
+	}
+
+	return v1.Convert_Pointer_bool_To_bool(&in.ScaleWorkloads, &out.ScaleWorkloads, s)
+}
+
+// Convert_config_EmissionsArgs_To_v1beta1_EmissionsArgs groups the internal
+// type into blocks.
+func Convert_config_EmissionsArgs_To_v1beta1_EmissionsArgs(in *config.EmissionsArgs, out *EmissionsArgs, s conversion.Scope) error {
+	if err := autoConvert_config_EmissionsArgs_To_v1beta1_EmissionsArgs(in, out, s); err != nil {
+		return err
+	}
+
+	out.Provider = &Provider{WattTime: &WattTime{}}
+
+	if err := v1.Convert_string_To_Pointer_string(&in.Provider, &out.Provider.Name, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.IndexRefreshInterval, &out.Provider.RefreshInterval, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_string_To_Pointer_string(&in.WattTimeUsername, &out.Provider.WattTime.Username, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_string_To_Pointer_string(&in.WattTimePassword, &out.Provider.WattTime.Password, s); err != nil {
+		return err
+	}
+
+	out.Provider.WattTime.CredentialsSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.CredentialsSecretRef))
+
+	if err := v1.Convert_string_To_Pointer_string(&in.CredentialsFile, &out.Provider.WattTime.CredentialsFile, s); err != nil {
+		return err
+	}
+
+	out.Regions = nil
+	if in.WattTimeBA != "" {
+		out.Regions = []Region{{Name: in.WattTimeBA}}
+	}
+
+	out.Policies = &Policies{}
+
+	if err := v1.Convert_string_To_Pointer_string(&in.DeferralMode, &out.Policies.DeferralMode, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_string_To_Pointer_string(&in.MaxWaitPolicy, &out.Policies.MaxWaitPolicy, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_string_To_Pointer_string(&in.FailurePolicy, &out.Policies.FailurePolicy, s); err != nil {
+		return err
+	}
+
+	out.PowerManagement = &PowerManagement{}
+
+	if err := convertPowerManagementFromInternal(in, out.PowerManagement, s); err != nil {
+		return err
+	}
+
+	out.Eviction = &Eviction{}
+
+	if err := convertEvictionFromInternal(in, out.Eviction, s); err != nil {
+		return err
+	}
+
+	out.Preemption = &Preemption{}
+
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MinCandidateNodesPercentage, &out.Preemption.MinCandidateNodesPercentage, s); err != nil {
+		return err
+	}
+
+	return v1.Convert_int32_To_Pointer_int32(&in.MinCandidateNodesAbsolute, &out.Preemption.MinCandidateNodesAbsolute, s)
+}
+
+func convertPowerManagementFromInternal(in *config.EmissionsArgs, out *PowerManagement, s conversion.Scope) error {
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.NodeIdleDuration, &out.NodeIdleDuration, s); err != nil {
+		return err
+	}
+
+	out.IdleIgnoredNamespaces = *(*[]string)(unsafe.Pointer(&in.IdleIgnoredNamespaces))
+	out.SchedulerNames = *(*[]string)(unsafe.Pointer(&in.SchedulerNames))
+
+	if err := v1.Convert_string_To_Pointer_string(&in.WakeOnLANBroadcastAddress, &out.WakeOnLANBroadcastAddress, s); err != nil {
+		return err
+	}
+
+	out.TalosConfigSecretRef = (*corev1.SecretReference)(unsafe.Pointer(in.TalosConfigSecretRef))
+
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.MinNodeUptime, &out.MinNodeUptime, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.MinNodeDowntime, &out.MinNodeDowntime, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_int32_To_Pointer_int32(&in.IndexHysteresis, &out.IndexHysteresis, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxPowerCyclesPerDay, &out.MaxPowerCyclesPerDay, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_string_To_Pointer_string(&in.NodePoolLabel, &out.NodePoolLabel, s); err != nil {
+		return err
+	}
+
+	out.NodePools = *(*[]NodePool)(unsafe.Pointer(&in.NodePools))
+
+	return nil
+}
+
+func convertEvictionFromInternal(in *config.EmissionsArgs, out *Eviction, s conversion.Scope) error {
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxEvictionsPerMinute, &out.MaxEvictionsPerMinute, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxNamespaceEvictionsPerMinute, &out.MaxNamespaceEvictionsPerMinute, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxEvictedReplicasPercent, &out.MaxEvictedReplicasPercent, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.EvictionConfirmationPeriod, &out.ConfirmationPeriod, s); err != nil {
+		return err
+	}
+
+	if err := v1.Convert_int32_To_Pointer_int32(&in.EvictionConfirmationSamples, &out.ConfirmationSamples, s); err != nil {
+		return err
+	}
+
+	out.GracePeriodSeconds = (*int64)(unsafe.Pointer(in.EvictionGracePeriodSeconds))
+
+	if err := v1.Convert_bool_To_Pointer_bool(&in.SuspendJobs, &out.SuspendJobs, s); err != nil {
+		return err
+	}
+
+	return v1.Convert_bool_To_Pointer_bool(&in.ScaleWorkloads, &out.ScaleWorkloads, s)
+}
@@ -0,0 +1,80 @@
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// SetDefaults_EmissionsArgs sets the default parameters for the Emissions
+// plugin.
+func SetDefaults_EmissionsArgs(obj *EmissionsArgs) {
+	if obj.Provider == nil {
+		obj.Provider = &Provider{}
+	}
+
+	if obj.Policies == nil {
+		obj.Policies = &Policies{}
+	}
+
+	if obj.PowerManagement == nil {
+		obj.PowerManagement = &PowerManagement{}
+	}
+
+	if obj.Preemption == nil {
+		obj.Preemption = &Preemption{}
+	}
+}
+
+// SetDefaults_Provider sets the default provider and refresh interval.
+func SetDefaults_Provider(obj *Provider) {
+	if obj.Name == nil {
+		obj.Name = pointer.String(config.ProviderWattTime)
+	}
+
+	if obj.RefreshInterval == nil {
+		obj.RefreshInterval = &metav1.Duration{Duration: 5 * time.Minute}
+	}
+}
+
+// SetDefaults_Policies sets the default deferral, max wait and failure
+// policies.
+func SetDefaults_Policies(obj *Policies) {
+	if obj.DeferralMode == nil {
+		obj.DeferralMode = pointer.String(config.DeferralModePreFilter)
+	}
+
+	if obj.MaxWaitPolicy == nil {
+		obj.MaxWaitPolicy = pointer.String(config.MaxWaitPolicyAllow)
+	}
+
+	if obj.FailurePolicy == nil {
+		obj.FailurePolicy = pointer.String(config.FailurePolicyDefer)
+	}
+}
+
+// SetDefaults_Preemption sets the default number of candidate nodes.
+func SetDefaults_Preemption(obj *Preemption) {
+	if obj.MinCandidateNodesPercentage == nil {
+		obj.MinCandidateNodesPercentage = pointer.Int32(config.DefaultMinCandidateNodesPercentage)
+	}
+
+	if obj.MinCandidateNodesAbsolute == nil {
+		obj.MinCandidateNodesAbsolute = pointer.Int32(config.DefaultMinCandidateNodesAbsolute)
+	}
+}
+
+// SetDefaults_PowerManagement sets the default idle duration and Wake-on-LAN
+// broadcast address.
+func SetDefaults_PowerManagement(obj *PowerManagement) {
+	if obj.NodeIdleDuration == nil {
+		obj.NodeIdleDuration = &metav1.Duration{Duration: 10 * time.Minute}
+	}
+
+	if obj.WakeOnLANBroadcastAddress == nil {
+		obj.WakeOnLANBroadcastAddress = pointer.String(config.DefaultWakeOnLANBroadcastAddress)
+	}
+}
@@ -0,0 +1,11 @@
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/siderolabs/kube-scheduler/apis/config
+// +k8s:defaulter-gen=TypeMeta
+// +k8s:defaulter-gen-input=github.com/siderolabs/kube-scheduler/apis/config/v1beta1
+
+// +groupName=kubescheduler.config.k8s.io
+
+// Package v1beta1 is the structured version of the Emissions plugin
+// arguments, grouping the provider, policy, power management and eviction
+// settings.
+package v1beta1
@@ -0,0 +1,203 @@
+// Package validation validates the Emissions plugin arguments.
+package validation
+
+import (
+	"net"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+var (
+	validProviders       = sets.New(config.ProviderWattTime)
+	validFailurePolicies = sets.New(config.FailurePolicyDefer, config.FailurePolicyAllow)
+	validDeferralModes   = sets.New(config.DeferralModePreFilter, config.DeferralModePermit)
+	validMaxWaitPolicies = sets.New(config.MaxWaitPolicyAllow, config.MaxWaitPolicyReject)
+)
+
+// ValidateEmissionsArgs validates the arguments of the Emissions plugin, after
+// defaulting.
+func ValidateEmissionsArgs(path *field.Path, args *config.EmissionsArgs) error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateProvider(path, args)...)
+
+	if args.IndexRefreshInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("indexRefreshInterval"), args.IndexRefreshInterval.Duration.String(), "must be greater than zero"))
+	}
+
+	allErrs = append(allErrs, validateOneOf(path.Child("failurePolicy"), args.FailurePolicy, validFailurePolicies)...)
+	allErrs = append(allErrs, validateOneOf(path.Child("deferralMode"), args.DeferralMode, validDeferralModes)...)
+	allErrs = append(allErrs, validateOneOf(path.Child("maxWaitPolicy"), args.MaxWaitPolicy, validMaxWaitPolicies)...)
+
+	allErrs = append(allErrs, validateNonNegativeDuration(path.Child("nodeIdleDuration"), args.NodeIdleDuration)...)
+	allErrs = append(allErrs, validateNonNegativeDuration(path.Child("minNodeUptime"), args.MinNodeUptime)...)
+	allErrs = append(allErrs, validateNonNegativeDuration(path.Child("minNodeDowntime"), args.MinNodeDowntime)...)
+	allErrs = append(allErrs, validateNonNegativeDuration(path.Child("evictionConfirmationPeriod"), args.EvictionConfirmationPeriod)...)
+
+	allErrs = append(allErrs, validateNonNegative(path.Child("indexHysteresis"), int64(args.IndexHysteresis))...)
+	allErrs = append(allErrs, validateNonNegative(path.Child("maxPowerCyclesPerDay"), int64(args.MaxPowerCyclesPerDay))...)
+	allErrs = append(allErrs, validateNonNegative(path.Child("maxEvictionsPerMinute"), int64(args.MaxEvictionsPerMinute))...)
+	allErrs = append(allErrs, validateNonNegative(path.Child("maxNamespaceEvictionsPerMinute"), int64(args.MaxNamespaceEvictionsPerMinute))...)
+	allErrs = append(allErrs, validateNonNegative(path.Child("evictionConfirmationSamples"), int64(args.EvictionConfirmationSamples))...)
+
+	if args.MaxEvictedReplicasPercent < 0 || args.MaxEvictedReplicasPercent > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxEvictedReplicasPercent"), args.MaxEvictedReplicasPercent, "must be between 0 and 100"))
+	}
+
+	allErrs = append(allErrs, validateCandidateNodes(path, args)...)
+
+	if args.EvictionGracePeriodSeconds != nil {
+		allErrs = append(allErrs, validateNonNegative(path.Child("evictionGracePeriodSeconds"), *args.EvictionGracePeriodSeconds)...)
+	}
+
+	if _, _, err := net.SplitHostPort(args.WakeOnLANBroadcastAddress); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("wakeOnLANBroadcastAddress"), args.WakeOnLANBroadcastAddress, err.Error()))
+	}
+
+	if args.TalosConfigSecretRef != nil && args.TalosConfigSecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("talosConfigSecretRef", "name"), ""))
+	}
+
+	if ref := args.DynamicConfigMapRef; ref != nil {
+		if ref.Namespace == "" {
+			allErrs = append(allErrs, field.Required(path.Child("dynamicConfigMapRef", "namespace"), ""))
+		}
+
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("dynamicConfigMapRef", "name"), ""))
+		}
+	}
+
+	allErrs = append(allErrs, validateNodePools(path, args)...)
+
+	return allErrs.ToAggregate()
+}
+
+func validateProvider(path *field.Path, args *config.EmissionsArgs) field.ErrorList {
+	allErrs := validateOneOf(path.Child("provider"), args.Provider, validProviders)
+
+	if args.Provider == config.ProviderWattTime {
+		allErrs = append(allErrs, validateCredentials(path, args)...)
+
+		if args.WattTimeBA == "" {
+			allErrs = append(allErrs, field.Required(path.Child("wattTimeBA"), "required by the WattTime provider"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateCredentials requires the credentials to be set either inline, from
+// a Secret or from a file.
+func validateCredentials(path *field.Path, args *config.EmissionsArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	inline := args.WattTimeUsername != "" || args.WattTimePassword != ""
+	sources := 0
+
+	for _, set := range []bool{inline, args.CredentialsSecretRef != nil, args.CredentialsFile != ""} {
+		if set {
+			sources++
+		}
+	}
+
+	switch {
+	case sources == 0:
+		allErrs = append(allErrs, field.Required(path.Child("wattTimeUsername"), "credentials are required by the WattTime provider, inline or from credentialsSecretRef or credentialsFile"))
+	case sources > 1:
+		allErrs = append(allErrs, field.Forbidden(path, "only one of wattTimeUsername and wattTimePassword, credentialsSecretRef or credentialsFile may be set"))
+	case inline:
+		if args.WattTimeUsername == "" {
+			allErrs = append(allErrs, field.Required(path.Child("wattTimeUsername"), "required with wattTimePassword"))
+		}
+
+		if args.WattTimePassword == "" {
+			allErrs = append(allErrs, field.Required(path.Child("wattTimePassword"), "required with wattTimeUsername"))
+		}
+	}
+
+	if ref := args.CredentialsSecretRef; ref != nil {
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("credentialsSecretRef", "name"), ""))
+		}
+
+		if ref.Namespace == "" {
+			allErrs = append(allErrs, field.Required(path.Child("credentialsSecretRef", "namespace"), ""))
+		}
+	}
+
+	return allErrs
+}
+
+// validateCandidateNodes validates the number of candidate nodes for
+// preemption as DefaultPreemption does.
+func validateCandidateNodes(path *field.Path, args *config.EmissionsArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if args.MinCandidateNodesPercentage < 0 || args.MinCandidateNodesPercentage > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("minCandidateNodesPercentage"), args.MinCandidateNodesPercentage, "must be between 0 and 100"))
+	}
+
+	allErrs = append(allErrs, validateNonNegative(path.Child("minCandidateNodesAbsolute"), int64(args.MinCandidateNodesAbsolute))...)
+
+	if args.MinCandidateNodesPercentage == 0 && args.MinCandidateNodesAbsolute == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("minCandidateNodesAbsolute"), args.MinCandidateNodesAbsolute, "must be greater than zero when minCandidateNodesPercentage is zero"))
+	}
+
+	return allErrs
+}
+
+func validateNodePools(path *field.Path, args *config.EmissionsArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(args.NodePools) > 0 && args.NodePoolLabel == "" {
+		allErrs = append(allErrs, field.Required(path.Child("nodePoolLabel"), "required when nodePools are set"))
+	}
+
+	names := sets.New[string]()
+
+	for i, pool := range args.NodePools {
+		poolPath := path.Child("nodePools").Index(i)
+
+		switch {
+		case pool.Name == "":
+			allErrs = append(allErrs, field.Required(poolPath.Child("name"), ""))
+		case names.Has(pool.Name):
+			allErrs = append(allErrs, field.Duplicate(poolPath.Child("name"), pool.Name))
+		}
+
+		names.Insert(pool.Name)
+
+		allErrs = append(allErrs, validateNonNegative(poolPath.Child("minOnlineNodes"), int64(pool.MinOnlineNodes))...)
+	}
+
+	return allErrs
+}
+
+func validateOneOf(path *field.Path, value string, valid sets.Set[string]) field.ErrorList {
+	if !valid.Has(value) {
+		return field.ErrorList{field.NotSupported(path, value, sets.List(valid))}
+	}
+
+	return nil
+}
+
+func validateNonNegative(path *field.Path, value int64) field.ErrorList {
+	if value < 0 {
+		return field.ErrorList{field.Invalid(path, value, "must be greater than or equal to zero")}
+	}
+
+	return nil
+}
+
+func validateNonNegativeDuration(path *field.Path, value metav1.Duration) field.ErrorList {
+	if value.Duration < 0 {
+		return field.ErrorList{field.Invalid(path, value.Duration.String(), "must be greater than or equal to zero")}
+	}
+
+	return nil
+}
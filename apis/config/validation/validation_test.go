@@ -0,0 +1,231 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/apis/config/scheme"
+	"github.com/siderolabs/kube-scheduler/apis/config/v1alpha1"
+)
+
+// validArgs returns defaulted arguments with inline WattTime credentials.
+func validArgs(t *testing.T) *config.EmissionsArgs {
+	t.Helper()
+
+	versioned := &v1alpha1.EmissionsArgs{
+		WattTimeUsername: pointer.String("user"),
+		WattTimePassword: pointer.String("password"),
+		WattTimeBA:       pointer.String("CAISO_NORTH"),
+	}
+
+	scheme.Scheme.Default(versioned)
+
+	args := &config.EmissionsArgs{}
+	if err := scheme.Scheme.Convert(versioned, args, nil); err != nil {
+		t.Fatalf("failed to convert args: %v", err)
+	}
+
+	return args
+}
+
+func TestValidateEmissionsArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(args *config.EmissionsArgs)
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			modify: func(args *config.EmissionsArgs) {},
+		},
+		{
+			name:    "unknown provider",
+			modify:  func(args *config.EmissionsArgs) { args.Provider = "Other" },
+			wantErr: true,
+		},
+		{
+			name: "no credentials",
+			modify: func(args *config.EmissionsArgs) {
+				args.WattTimeUsername = ""
+				args.WattTimePassword = ""
+			},
+			wantErr: true,
+		},
+		{
+			name:    "username without password",
+			modify:  func(args *config.EmissionsArgs) { args.WattTimePassword = "" },
+			wantErr: true,
+		},
+		{
+			name: "credentials from a Secret",
+			modify: func(args *config.EmissionsArgs) {
+				args.WattTimeUsername = ""
+				args.WattTimePassword = ""
+				args.CredentialsSecretRef = &v1.SecretReference{Namespace: "kube-system", Name: "watttime"}
+			},
+		},
+		{
+			name: "credentials Secret without namespace",
+			modify: func(args *config.EmissionsArgs) {
+				args.WattTimeUsername = ""
+				args.WattTimePassword = ""
+				args.CredentialsSecretRef = &v1.SecretReference{Name: "watttime"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "credentials from a file",
+			modify: func(args *config.EmissionsArgs) {
+				args.WattTimeUsername = ""
+				args.WattTimePassword = ""
+				args.CredentialsFile = "/etc/watttime/credentials.yaml"
+			},
+		},
+		{
+			name:    "credentials inline and from a file",
+			modify:  func(args *config.EmissionsArgs) { args.CredentialsFile = "/etc/watttime/credentials.yaml" },
+			wantErr: true,
+		},
+		{
+			name:    "no BA",
+			modify:  func(args *config.EmissionsArgs) { args.WattTimeBA = "" },
+			wantErr: true,
+		},
+		{
+			name:    "zero refresh interval",
+			modify:  func(args *config.EmissionsArgs) { args.IndexRefreshInterval = metav1.Duration{} },
+			wantErr: true,
+		},
+		{
+			name:    "unknown failure policy",
+			modify:  func(args *config.EmissionsArgs) { args.FailurePolicy = "Retry" },
+			wantErr: true,
+		},
+		{
+			name:    "unknown deferral mode",
+			modify:  func(args *config.EmissionsArgs) { args.DeferralMode = "Filter" },
+			wantErr: true,
+		},
+		{
+			name:    "unknown max wait policy",
+			modify:  func(args *config.EmissionsArgs) { args.MaxWaitPolicy = "Ignore" },
+			wantErr: true,
+		},
+		{
+			name:    "negative duration",
+			modify:  func(args *config.EmissionsArgs) { args.MinNodeUptime = metav1.Duration{Duration: -time.Minute} },
+			wantErr: true,
+		},
+		{
+			name:    "negative hysteresis",
+			modify:  func(args *config.EmissionsArgs) { args.IndexHysteresis = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative eviction rate",
+			modify:  func(args *config.EmissionsArgs) { args.MaxNamespaceEvictionsPerMinute = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "evicted replicas percent above 100",
+			modify:  func(args *config.EmissionsArgs) { args.MaxEvictedReplicasPercent = 101 },
+			wantErr: true,
+		},
+		{
+			name:    "negative grace period",
+			modify:  func(args *config.EmissionsArgs) { args.EvictionGracePeriodSeconds = pointer.Int64(-1) },
+			wantErr: true,
+		},
+		{
+			name:    "candidate nodes percentage above 100",
+			modify:  func(args *config.EmissionsArgs) { args.MinCandidateNodesPercentage = 101 },
+			wantErr: true,
+		},
+		{
+			name: "no candidate nodes",
+			modify: func(args *config.EmissionsArgs) {
+				args.MinCandidateNodesPercentage = 0
+				args.MinCandidateNodesAbsolute = 0
+			},
+			wantErr: true,
+		},
+		{
+			name: "absolute candidate nodes only",
+			modify: func(args *config.EmissionsArgs) {
+				args.MinCandidateNodesPercentage = 0
+			},
+		},
+		{
+			name:    "broadcast address without port",
+			modify:  func(args *config.EmissionsArgs) { args.WakeOnLANBroadcastAddress = "192.168.1.255" },
+			wantErr: true,
+		},
+		{
+			name:    "talosconfig Secret without name",
+			modify:  func(args *config.EmissionsArgs) { args.TalosConfigSecretRef = &v1.SecretReference{} },
+			wantErr: true,
+		},
+		{
+			name: "dynamic ConfigMap without namespace",
+			modify: func(args *config.EmissionsArgs) {
+				args.DynamicConfigMapRef = &config.ConfigMapReference{Name: "emissions"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "node pools",
+			modify: func(args *config.EmissionsArgs) {
+				args.NodePoolLabel = "pool"
+				args.NodePools = []config.NodePool{{Name: "workers", MinOnlineNodes: 2}, {Name: "gpu"}}
+			},
+		},
+		{
+			name: "node pools without label",
+			modify: func(args *config.EmissionsArgs) {
+				args.NodePools = []config.NodePool{{Name: "workers", MinOnlineNodes: 2}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "node pool without name",
+			modify: func(args *config.EmissionsArgs) {
+				args.NodePoolLabel = "pool"
+				args.NodePools = []config.NodePool{{MinOnlineNodes: 2}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate node pools",
+			modify: func(args *config.EmissionsArgs) {
+				args.NodePoolLabel = "pool"
+				args.NodePools = []config.NodePool{{Name: "workers"}, {Name: "workers"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative minimum online nodes",
+			modify: func(args *config.EmissionsArgs) {
+				args.NodePoolLabel = "pool"
+				args.NodePools = []config.NodePool{{Name: "workers", MinOnlineNodes: -1}}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := validArgs(t)
+			tt.modify(args)
+
+			err := ValidateEmissionsArgs(nil, args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEmissionsArgs() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
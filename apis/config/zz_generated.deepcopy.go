@@ -6,13 +6,70 @@
 package config
 
 import (
+	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmissionsArgs) DeepCopyInto(out *EmissionsArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	out.NodeIdleDuration = in.NodeIdleDuration
+	if in.IdleIgnoredNamespaces != nil {
+		in, out := &in.IdleIgnoredNamespaces, &out.IdleIgnoredNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchedulerNames != nil {
+		in, out := &in.SchedulerNames, &out.SchedulerNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TalosConfigSecretRef != nil {
+		in, out := &in.TalosConfigSecretRef, &out.TalosConfigSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	out.MinNodeUptime = in.MinNodeUptime
+	out.MinNodeDowntime = in.MinNodeDowntime
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		copy(*out, *in)
+	}
+	out.IndexRefreshInterval = in.IndexRefreshInterval
+	out.EvictionConfirmationPeriod = in.EvictionConfirmationPeriod
+	if in.EvictionGracePeriodSeconds != nil {
+		in, out := &in.EvictionGracePeriodSeconds, &out.EvictionGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DynamicConfigMapRef != nil {
+		in, out := &in.DynamicConfigMapRef, &out.DynamicConfigMapRef
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
 	return
 }
 
@@ -33,3 +90,19 @@ func (in *EmissionsArgs) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/cli"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/apis/config/scheme"
+	"github.com/siderolabs/kube-scheduler/apis/config/validation"
+	"github.com/siderolabs/kube-scheduler/pkg/controllers"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+	"github.com/siderolabs/kube-scheduler/pkg/energy/watttime"
+)
+
+func main() {
+	var (
+		kubeconfig      string
+		configFile      string
+		healthProbeAddr string
+		leaderElect     bool
+		leaseNamespace  string
+		leaseName       string
+		identity        string
+	)
+
+	command := &cobra.Command{
+		Use:   "carbon-controller",
+		Short: "Evicts pods and powers nodes on and off based on the emissions index, next to any scheduler.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if configFile == "" {
+				return fmt.Errorf("--config is required")
+			}
+
+			args, err := scheme.LoadEmissionsArgs(configFile)
+			if err != nil {
+				return err
+			}
+
+			if err = validation.ValidateEmissionsArgs(nil, args); err != nil {
+				return fmt.Errorf("invalid %s: %w", configFile, err)
+			}
+
+			restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+			if err != nil {
+				return err
+			}
+
+			clientset, err := kubernetes.NewForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+
+			// The index is published for schedulers to requeue deferred pods
+			// on, while the controllers run.
+			dynamicClient, err := dynamic.NewForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+
+			ctx := genericapiserver.SetupSignalContext()
+
+			indexCache, err := watttime.NewCache(args, clientset, ctx.Done())
+			if err != nil {
+				return err
+			}
+
+			indexCache.Run(ctx.Done())
+
+			go serveHealthProbes(ctx, healthProbeAddr, indexCache)
+
+			options := controllers.DefaultOptions()
+			options.LeaderElect = leaderElect
+			options.LeaseNamespace = leaseNamespace
+			options.LeaseName = leaseName
+
+			if identity != "" {
+				options.Identity = identity
+			}
+
+			informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+
+			// Tunable settings follow the dynamic ConfigMap, if any.
+			source := dynamicconfig.NewSource(args)
+			source.OnChange(func(args *config.EmissionsArgs) {
+				indexCache.SetInterval(args.IndexRefreshInterval.Duration)
+			})
+
+			go source.Run(clientset, ctx.Done())
+
+			controllerRuntime, err := controllers.NewRuntime(clientset, dynamicClient, informerFactory, indexCache, source, options)
+			if err != nil {
+				return err
+			}
+
+			informerFactory.Start(ctx.Done())
+
+			return controllerRuntime.Run(ctx)
+		},
+	}
+
+	command.Flags().StringVar(&kubeconfig, "kubeconfig", "", "path to a kubeconfig, the in-cluster configuration is used if empty")
+	command.Flags().StringVar(&configFile, "config", "", "path to an EmissionsArgs file")
+	command.Flags().StringVar(&healthProbeAddr, "health-probe-bind-address", ":8081", "address serving /healthz and /readyz")
+	command.Flags().BoolVar(&leaderElect, "leader-elect", true, "run the controllers only while holding the Lease")
+	command.Flags().StringVar(&leaseNamespace, "leader-elect-resource-namespace", controllers.DefaultLeaseNamespace, "namespace of the Lease")
+	command.Flags().StringVar(&leaseName, "leader-elect-resource-name", controllers.DefaultLeaseName, "name of the Lease")
+	command.Flags().StringVar(&identity, "leader-elect-identity", "", "identity in the Lease, the hostname with a random suffix if empty")
+
+	code := cli.Run(command)
+	os.Exit(code)
+}
+
+// serveHealthProbes serves /healthz, and /readyz once the index is known.
+func serveHealthProbes(ctx context.Context, addr string, indexCache *energy.Cache) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if _, err := indexCache.Index(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.Write([]byte("ok"))
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("failed to serve health probes: %v", err)
+	}
+}
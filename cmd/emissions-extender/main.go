@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/cli"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/apis/config/scheme"
+	"github.com/siderolabs/kube-scheduler/apis/config/validation"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+	"github.com/siderolabs/kube-scheduler/pkg/energy/watttime"
+	"github.com/siderolabs/kube-scheduler/pkg/extender"
+)
+
+func main() {
+	var (
+		kubeconfig  string
+		configFile  string
+		bindAddress string
+		certFile    string
+		keyFile     string
+	)
+
+	command := &cobra.Command{
+		Use:   "emissions-extender",
+		Short: "Serves the emissions filter and prioritize logic as a scheduler extender.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if configFile == "" {
+				return fmt.Errorf("--config is required")
+			}
+
+			if (certFile == "") != (keyFile == "") {
+				return fmt.Errorf("--tls-cert-file and --tls-private-key-file must be set together")
+			}
+
+			args, err := scheme.LoadEmissionsArgs(configFile)
+			if err != nil {
+				return err
+			}
+
+			if err = validation.ValidateEmissionsArgs(nil, args); err != nil {
+				return fmt.Errorf("invalid %s: %w", configFile, err)
+			}
+
+			ctx := genericapiserver.SetupSignalContext()
+
+			// A clientset is only needed to read credentials from a Secret
+			// and to follow the dynamic ConfigMap.
+			var clientset kubernetes.Interface
+
+			if args.CredentialsSecretRef != nil || args.DynamicConfigMapRef != nil {
+				restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+				if err != nil {
+					return err
+				}
+
+				if clientset, err = kubernetes.NewForConfig(restConfig); err != nil {
+					return err
+				}
+			}
+
+			indexCache, err := watttime.NewCache(args, clientset, ctx.Done())
+			if err != nil {
+				return err
+			}
+
+			indexCache.Run(ctx.Done())
+
+			// Tunable settings follow the dynamic ConfigMap, if any.
+			source := dynamicconfig.NewSource(args)
+			source.OnChange(func(args *config.EmissionsArgs) {
+				indexCache.SetInterval(args.IndexRefreshInterval.Duration)
+			})
+
+			go source.Run(clientset, ctx.Done())
+
+			mux := http.NewServeMux()
+			mux.Handle("/", extender.New(indexCache, source).Handler())
+			mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte("ok"))
+			})
+			mux.HandleFunc("/readyz", readyz(indexCache))
+
+			server := &http.Server{Addr: bindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+			go func() {
+				<-ctx.Done()
+				server.Close()
+			}()
+
+			log.Printf("serving extender on %s", bindAddress)
+
+			if certFile != "" {
+				err = server.ListenAndServeTLS(certFile, keyFile)
+			} else {
+				err = server.ListenAndServe()
+			}
+
+			if err != nil && err != http.ErrServerClosed {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&kubeconfig, "kubeconfig", "", "path to a kubeconfig used to read credentialsSecretRef and dynamicConfigMapRef, the in-cluster configuration is used if empty")
+	command.Flags().StringVar(&configFile, "config", "", "path to an EmissionsArgs file")
+	command.Flags().StringVar(&bindAddress, "bind-address", ":8888", "address serving the extender, /healthz and /readyz")
+	command.Flags().StringVar(&certFile, "tls-cert-file", "", "certificate served over HTTPS, plain HTTP is served if empty")
+	command.Flags().StringVar(&keyFile, "tls-private-key-file", "", "private key of --tls-cert-file")
+
+	code := cli.Run(command)
+	os.Exit(code)
+}
+
+// readyz serves ok once the index is known.
+func readyz(indexCache *energy.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if _, err := indexCache.Index(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.Write([]byte("ok"))
+	}
+}
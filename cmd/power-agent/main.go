@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/cli"
+
+	"github.com/siderolabs/kube-scheduler/pkg/agent"
+)
+
+func main() {
+	var (
+		nodeName        string
+		shutdownCommand []string
+	)
+
+	command := &cobra.Command{
+		Use:   "power-agent",
+		Short: "Shuts down the node it runs on when asked to by the scheduler.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if nodeName == "" {
+				return fmt.Errorf("--node-name is required")
+			}
+
+			config, err := rest.InClusterConfig()
+			if err != nil {
+				return err
+			}
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return err
+			}
+
+			return agent.NewAgent(clientset, nodeName, shutdownCommand).Run(genericapiserver.SetupSignalContext())
+		},
+	}
+
+	command.Flags().StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "name of the node the agent runs on")
+	command.Flags().StringSliceVar(&shutdownCommand, "shutdown-command", agent.DefaultShutdownCommand, "command run to shut the node down")
+
+	code := cli.Run(command)
+	os.Exit(code)
+}
@@ -1,17 +1,30 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/component-base/cli"
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	_ "github.com/siderolabs/kube-scheduler/apis/config/scheme"
 	"github.com/siderolabs/kube-scheduler/pkg/plugins/emissions"
 )
 
 func main() {
-	command := app.NewSchedulerCommand(app.WithPlugin(emissions.Name, emissions.New))
+	// The plugin's background work, such as the controllers holding their
+	// Lease, stops on SIGINT or SIGTERM along with the scheduler.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	command := app.NewSchedulerCommand(app.WithPlugin(emissions.Name, func(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+		return emissions.New(ctx, obj, h)
+	}))
 	code := cli.Run(command)
+
+	cancel()
 	os.Exit(code)
 }
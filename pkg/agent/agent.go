@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ShutdownAnnotation is set on a node to ask the agent running on it to shut
+// the machine down. The value is the RFC 3339 time of the request. It is
+// cleared once the node is powered on again, the agent only reading nodes.
+const ShutdownAnnotation = "power.siderolabs.com/shutdown-requested"
+
+// DefaultShutdownCommand powers off the host from a privileged container
+// sharing the host PID namespace.
+var DefaultShutdownCommand = []string{"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--", "poweroff"}
+
+// RequestShutdown asks the agent on the node to shut the machine down.
+func RequestShutdown(ctx context.Context, clientset kubernetes.Interface, nodeName string) error {
+	return patchAnnotation(ctx, clientset, nodeName, time.Now().UTC().Format(time.RFC3339))
+}
+
+func patchAnnotation(ctx context.Context, clientset kubernetes.Interface, nodeName string, value string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				ShutdownAnnotation: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
+
+// Agent runs on every node and shuts the machine down when asked to.
+type Agent struct {
+	clientset kubernetes.Interface
+	nodeName  string
+	command   []string
+	startedAt time.Time
+	requests  chan string
+	// handled is the last request handled.
+	handled string
+}
+
+// NewAgent creates an Agent for the named node.
+func NewAgent(clientset kubernetes.Interface, nodeName string, command []string) *Agent {
+	if len(command) == 0 {
+		command = DefaultShutdownCommand
+	}
+
+	return &Agent{
+		clientset: clientset,
+		nodeName:  nodeName,
+		command:   command,
+		startedAt: time.Now(),
+		requests:  make(chan string, 1),
+	}
+}
+
+// Run watches the node and handles shutdown requests until the context is
+// canceled.
+func (a *Agent) Run(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(a.clientset, 5*time.Minute,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", a.nodeName).String()
+		}),
+	)
+
+	nodeInformer := factory.Core().V1().Nodes()
+
+	_, err := nodeInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: a.nodeAdd,
+			UpdateFunc: func(old, new interface{}) {
+				a.nodeAdd(new)
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), nodeInformer.Informer().HasSynced) {
+		return fmt.Errorf("failed to sync")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case requested := <-a.requests:
+			a.shutdown(ctx, requested)
+		}
+	}
+}
+
+func (a *Agent) nodeAdd(obj interface{}) {
+	node := obj.(*v1.Node)
+
+	requested, ok := node.Annotations[ShutdownAnnotation]
+	if !ok {
+		return
+	}
+
+	select {
+	case a.requests <- requested:
+	default:
+	}
+}
+
+func (a *Agent) shutdown(ctx context.Context, requested string) {
+	// The request stays on the node until it is powered on again, so it is
+	// handled once.
+	if requested == a.handled {
+		return
+	}
+
+	a.handled = requested
+
+	requestedAt, err := time.Parse(time.RFC3339, requested)
+	if err != nil {
+		log.Printf("ignoring malformed shutdown request %q on %q", requested, a.nodeName)
+
+		return
+	}
+
+	// Requests made before this agent started were meant for the previous
+	// boot.
+	if requestedAt.Before(a.startedAt.Truncate(time.Second)) {
+		log.Printf("ignoring stale shutdown request from %s on %q", requested, a.nodeName)
+
+		return
+	}
+
+	log.Printf("shutting down %q", a.nodeName)
+
+	out, err := exec.CommandContext(ctx, a.command[0], a.command[1:]...).CombinedOutput()
+	if err != nil {
+		log.Printf("failed to shut down %q: %v: %s", a.nodeName, err, out)
+	}
+}
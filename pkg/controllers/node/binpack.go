@@ -0,0 +1,175 @@
+package node
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+)
+
+// simulatedNode tracks the resources a powered-off node has left while
+// pending pods are packed onto it.
+type simulatedNode struct {
+	node      *v1.Node
+	available v1.ResourceList
+	pods      int64
+}
+
+func newSimulatedNode(node *v1.Node) *simulatedNode {
+	available := node.Status.Allocatable.DeepCopy()
+	if len(available) == 0 {
+		available = node.Status.Capacity.DeepCopy()
+	}
+
+	pods := available.Pods().Value()
+	delete(available, v1.ResourcePods)
+
+	return &simulatedNode{node: node, available: available, pods: pods}
+}
+
+// fits reports whether the pod can be placed on the node given the node's
+// labels, taints and remaining resources.
+func (n *simulatedNode) fits(pod *v1.Pod, requests v1.ResourceList) bool {
+	if n.pods <= 0 {
+		return false
+	}
+
+	match, err := nodeaffinity.GetRequiredNodeAffinity(pod).Match(n.node)
+	if err != nil || !match {
+		return false
+	}
+
+	_, untolerated := corev1helpers.FindMatchingUntoleratedTaint(n.node.Spec.Taints, pod.Spec.Tolerations, schedulingTaint)
+	if untolerated {
+		return false
+	}
+
+	for name, quantity := range requests {
+		if quantity.IsZero() {
+			continue
+		}
+
+		available, ok := n.available[name]
+		if !ok || available.Cmp(quantity) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// place reserves the pod's requests on the node.
+func (n *simulatedNode) place(requests v1.ResourceList) {
+	for name, quantity := range requests {
+		available, ok := n.available[name]
+		if !ok {
+			continue
+		}
+
+		available.Sub(quantity)
+		n.available[name] = available
+	}
+
+	n.pods--
+}
+
+// schedulingTaint selects the taints the scheduler would honor once the node
+// is back. Taints added because the node is unreachable or not ready are
+// expected on a powered-off node and go away once it boots.
+func schedulingTaint(taint *v1.Taint) bool {
+	if taint.Key == v1.TaintNodeNotReady || taint.Key == v1.TaintNodeUnreachable {
+		return false
+	}
+
+	return taint.Effect == v1.TaintEffectNoSchedule || taint.Effect == v1.TaintEffectNoExecute
+}
+
+// packPendingPods simulates placing the pending pods onto the powered-off
+// nodes and returns the names of the smallest set of nodes found that fits
+// them. Pods are packed largest first, preferring nodes already selected and
+// otherwise the largest powered-off node they fit on.
+func packPendingPods(pending []*v1.Pod, poweredOff []*v1.Node) sets.Set[string] {
+	selected := sets.New[string]()
+
+	requests := make(map[*v1.Pod]v1.ResourceList, len(pending))
+	for _, pod := range pending {
+		requests[pod] = resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+	}
+
+	pods := append([]*v1.Pod(nil), pending...)
+	sort.SliceStable(pods, func(i, j int) bool {
+		return largerThan(requests[pods[i]], requests[pods[j]])
+	})
+
+	candidates := make([]*simulatedNode, 0, len(poweredOff))
+	for _, node := range poweredOff {
+		candidates = append(candidates, newSimulatedNode(node))
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if largerThan(candidates[i].available, candidates[j].available) {
+			return true
+		}
+
+		if largerThan(candidates[j].available, candidates[i].available) {
+			return false
+		}
+
+		return candidates[i].node.Name < candidates[j].node.Name
+	})
+
+	for _, pod := range pods {
+		var target *simulatedNode
+
+		for _, candidate := range candidates {
+			if selected.Has(candidate.node.Name) && candidate.fits(pod, requests[pod]) {
+				target = candidate
+
+				break
+			}
+		}
+
+		if target == nil {
+			for _, candidate := range candidates {
+				if !selected.Has(candidate.node.Name) && candidate.fits(pod, requests[pod]) {
+					target = candidate
+
+					break
+				}
+			}
+		}
+
+		if target == nil {
+			continue
+		}
+
+		target.place(requests[pod])
+		selected.Insert(target.node.Name)
+	}
+
+	return selected
+}
+
+// largerThan orders resource lists by CPU, then by memory.
+func largerThan(a, b v1.ResourceList) bool {
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		qa, qb := quantity(a, name), quantity(b, name)
+		if c := qa.Cmp(qb); c != 0 {
+			return c > 0
+		}
+	}
+
+	return false
+}
+
+func quantity(list v1.ResourceList, name v1.ResourceName) resource.Quantity {
+	if q, ok := list[name]; ok {
+		return q
+	}
+
+	return resource.Quantity{}
+}
@@ -0,0 +1,98 @@
+package node
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestPackPendingPods(t *testing.T) {
+	tainted := testNode("tainted", "8", "32Gi", false)
+	tainted.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+
+	notReady := testNode("not-ready", "2", "8Gi", false)
+	notReady.Spec.Taints = []v1.Taint{{Key: v1.TaintNodeNotReady, Effect: v1.TaintEffectNoSchedule}}
+
+	labeled := testNode("labeled", "2", "8Gi", false)
+	labeled.Labels["zone"] = "a"
+
+	inZone := withRequests(testPod("in-zone", 100), "1", "1Gi")
+	inZone.Spec.NodeSelector = map[string]string{"zone": "a"}
+
+	tests := []struct {
+		name       string
+		pending    []*v1.Pod
+		poweredOff []*v1.Node
+		want       []string
+	}{
+		{
+			name:       "no pending pods",
+			poweredOff: []*v1.Node{testNode("a", "4", "16Gi", false)},
+		},
+		{
+			name: "pods share a node",
+			pending: []*v1.Pod{
+				withRequests(testPod("p1", 100), "1", "1Gi"),
+				withRequests(testPod("p2", 100), "1", "1Gi"),
+			},
+			poweredOff: []*v1.Node{testNode("a", "4", "16Gi", false), testNode("b", "4", "16Gi", false)},
+			want:       []string{"a"},
+		},
+		{
+			name: "largest node first",
+			pending: []*v1.Pod{
+				withRequests(testPod("p1", 100), "1", "1Gi"),
+			},
+			poweredOff: []*v1.Node{testNode("small", "2", "4Gi", false), testNode("large", "8", "32Gi", false)},
+			want:       []string{"large"},
+		},
+		{
+			name: "pods spill onto another node",
+			pending: []*v1.Pod{
+				withRequests(testPod("p1", 100), "3", "1Gi"),
+				withRequests(testPod("p2", 100), "3", "1Gi"),
+			},
+			poweredOff: []*v1.Node{testNode("a", "4", "16Gi", false), testNode("b", "4", "16Gi", false)},
+			want:       []string{"a", "b"},
+		},
+		{
+			name: "pod fitting nowhere",
+			pending: []*v1.Pod{
+				withRequests(testPod("p1", 100), "16", "1Gi"),
+			},
+			poweredOff: []*v1.Node{testNode("a", "4", "16Gi", false)},
+		},
+		{
+			name: "untolerated taint",
+			pending: []*v1.Pod{
+				withRequests(testPod("p1", 100), "1", "1Gi"),
+			},
+			poweredOff: []*v1.Node{tainted},
+		},
+		{
+			name: "not ready taint is ignored",
+			pending: []*v1.Pod{
+				withRequests(testPod("p1", 100), "1", "1Gi"),
+			},
+			poweredOff: []*v1.Node{notReady},
+			want:       []string{"not-ready"},
+		},
+		{
+			name:       "node selector",
+			pending:    []*v1.Pod{inZone},
+			poweredOff: []*v1.Node{testNode("large", "8", "32Gi", false), labeled},
+			want:       []string{"labeled"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := packPendingPods(tt.pending, tt.poweredOff)
+
+			if !got.Equal(sets.New(tt.want...)) {
+				t.Errorf("packPendingPods() = %v, want %v", sets.List(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/siderolabs/kube-scheduler/pkg/agent"
+	"github.com/siderolabs/kube-scheduler/pkg/bmc"
+	"github.com/siderolabs/kube-scheduler/pkg/power"
+	"github.com/siderolabs/kube-scheduler/pkg/talos"
+	"github.com/siderolabs/kube-scheduler/pkg/wol"
+)
+
+const wolBroadcastAnnotation = "wol.siderolabs.com/broadcast"
+
+// shutdownMethodAnnotation selects how a node is powered off. Nodes with a
+// BMC default to shutdownMethodBMC, others to shutdownMethodAgent.
+const shutdownMethodAnnotation = "power.siderolabs.com/shutdown-method"
+
+const (
+	shutdownMethodBMC   = "bmc"
+	shutdownMethodAgent = "agent"
+	shutdownMethodTalos = "talos"
+)
+
+const talosConfigSecretKey = "talosconfig"
+
+// bootTimeout is how long a node woken up over Wake-on-LAN is taken as on
+// while it boots, before it is woken up again.
+const bootTimeout = 10 * time.Minute
+
+// driverFor returns the power driver for the node, or nil if the node is not
+// power managed.
+func (c *NodeManager) driverFor(node *v1.Node) (power.Driver, error) {
+	if endpoint, ok := node.Annotations[power.BMCEndpointAnnotation]; ok {
+		user, ok := node.Annotations[bmcUserAnnotation]
+		if !ok {
+			return nil, nil
+		}
+
+		pass, ok := node.Annotations[bmcPasswordAnnotation]
+		if !ok {
+			return nil, nil
+		}
+
+		client, err := bmc.NewClient(&bmc.BMCInfo{Endpoint: endpoint, User: user, Pass: pass})
+		if err != nil {
+			return nil, err
+		}
+
+		method := shutdownMethod(node, shutdownMethodBMC)
+		if method == shutdownMethodBMC {
+			return client, nil
+		}
+
+		shutdown, err := c.shutdownFor(node, method)
+		if err != nil {
+			client.Close()
+
+			return nil, err
+		}
+
+		return &gracefulDriver{Driver: client, shutdown: shutdown}, nil
+	}
+
+	if mac, ok := node.Annotations[power.WOLMACAnnotation]; ok {
+		broadcastAddress, ok := node.Annotations[wolBroadcastAnnotation]
+		if !ok {
+			broadcastAddress = c.args.Args().WakeOnLANBroadcastAddress
+		}
+
+		client, err := wol.NewClient(mac, broadcastAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		shutdown, err := c.shutdownFor(node, shutdownMethod(node, shutdownMethodAgent))
+		if err != nil {
+			return nil, err
+		}
+
+		return &wolDriver{client: client, shutdown: shutdown, node: node}, nil
+	}
+
+	return nil, nil
+}
+
+func shutdownMethod(node *v1.Node, fallback string) string {
+	if method, ok := node.Annotations[shutdownMethodAnnotation]; ok {
+		return method
+	}
+
+	return fallback
+}
+
+// shutdownFor returns the function that gracefully shuts the node down with
+// the given method.
+func (c *NodeManager) shutdownFor(node *v1.Node, method string) (func() error, error) {
+	switch method {
+	case shutdownMethodAgent:
+		return func() error {
+			return agent.RequestShutdown(context.TODO(), c.clientset, node.Name)
+		}, nil
+	case shutdownMethodTalos:
+		return func() error {
+			return c.talosShutdown(node)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported shutdown method %q for node %q", method, node.Name)
+	}
+}
+
+// talosShutdown shuts the node down through the Talos machine API using the
+// talosconfig stored in the configured Secret.
+func (c *NodeManager) talosShutdown(node *v1.Node) error {
+	ref := c.args.Args().TalosConfigSecretRef
+	if ref == nil {
+		return fmt.Errorf("talosConfigSecretRef is not configured")
+	}
+
+	secret, err := c.clientset.CoreV1().Secrets(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get talosconfig secret: %w", err)
+	}
+
+	cfg, err := talos.ParseConfig(secret.Data[talosConfigSecretKey])
+	if err != nil {
+		return err
+	}
+
+	address := nodeAddress(node)
+	if address == "" {
+		return fmt.Errorf("node %q has no internal address", node.Name)
+	}
+
+	client, err := talos.NewClient(cfg, address)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.Shutdown(context.TODO())
+}
+
+func nodeAddress(node *v1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == v1.NodeInternalIP {
+			return address.Address
+		}
+	}
+
+	return ""
+}
+
+// gracefulDriver uses the wrapped driver for everything but powering off.
+type gracefulDriver struct {
+	power.Driver
+	shutdown func() error
+}
+
+func (d *gracefulDriver) PowerOff() error {
+	return d.shutdown()
+}
+
+// wolDriver powers nodes on with a Wake-on-LAN magic packet and off with an
+// in-cluster shutdown.
+type wolDriver struct {
+	client   *wol.Client
+	shutdown func() error
+	node     *v1.Node
+}
+
+func (d *wolDriver) PowerOn() error {
+	return d.client.Wake()
+}
+
+func (d *wolDriver) PowerOff() error {
+	return d.shutdown()
+}
+
+// IsPoweredOn uses node readiness, as there is no way to query the machine
+// directly. A node woken up recently is taken as on while it boots, so that
+// it is not woken up again on every node event.
+func (d *wolDriver) IsPoweredOn() (bool, error) {
+	if isReady(d.node) {
+		return true, nil
+	}
+
+	state := readPowerState(d.node)
+
+	return !state.poweringOff(time.Now()) && !state.lastPowerOn.IsZero() && time.Since(state.lastPowerOn) < bootTimeout, nil
+}
+
+func (d *wolDriver) Close() error {
+	return nil
+}
@@ -1,85 +1,152 @@
 package node
 
 import (
-	"context"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	klog "k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 
 	"github.com/siderolabs/kube-scheduler/pkg/bmc"
-	"github.com/siderolabs/kube-scheduler/pkg/energy/watttime"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+	"github.com/siderolabs/kube-scheduler/pkg/power"
 )
 
-const bmcEndpointAnnotation = "bmc.siderolabs.com/endpoint"
 const bmcUserAnnotation = "bmc.siderolabs.com/username"
 const bmcPasswordAnnotation = "bmc.siderolabs.com/password"
 
+const nodeNameIndex = "nodeName"
+
+// resyncPeriod is how often all nodes are reconsidered.
+const resyncPeriod = 5 * time.Minute
+
 type BMCs map[string]*bmc.BMCInfo
 
 // NodeManager manages the power state of nodes.
 type NodeManager struct {
-	informerFactory informers.SharedInformerFactory
-	nodeInformer    coreinformers.NodeInformer
-	clientset       *kubernetes.Clientset
-	wattTimeClient  *watttime.Client
+	nodeInformer coreinformers.NodeInformer
+	podInformer  coreinformers.PodInformer
+	clientset    kubernetes.Interface
+	indexCache   *energy.Cache
+	args         *dynamicconfig.Source
+
+	// powerMu serializes power decisions, which are made both from the
+	// informer and on resync, so that nodes of a pool are not powered off
+	// together.
+	powerMu sync.Mutex
+
+	mu sync.Mutex
+	// emptySince records when each node was first seen without workload pods,
+	// since it last had some or was powered on or off.
+	emptySince map[string]time.Time
+	// poweredOff records when each node was powered off, until the informer
+	// catches up with the node's annotations.
+	poweredOff map[string]time.Time
+	// profileNames are the scheduler profiles running the Emissions plugin.
+	profileNames sets.Set[string]
 }
 
-// Run starts shared informers and waits for the shared informer cache to
-// synchronize.
+// Run waits for the shared informer cache to synchronize and manages nodes
+// until stopCh is closed. The informer factory is started by the caller, and
+// a NodeManager may be run again once stopped.
 func (c *NodeManager) Run(stopCh <-chan struct{}) error {
-	c.informerFactory.Start(stopCh)
-	if !cache.WaitForCacheSync(stopCh, c.nodeInformer.Informer().HasSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.nodeInformer.Informer().HasSynced, c.podInformer.Informer().HasSynced) {
 		return fmt.Errorf("failed to sync")
 	}
 
-	return nil
-}
+	registration, err := c.nodeInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.nodeAdd,
+			UpdateFunc: c.nodeUpdate,
+			DeleteFunc: c.nodeDelete,
+		},
+	)
+	if err != nil {
+		return err
+	}
 
-func (c *NodeManager) nodeAdd(obj interface{}) {
-	node := obj.(*v1.Node)
+	// Pods may come and go between node updates, so they end the idle
+	// period of their node as they are assigned.
+	podRegistration, err := c.podInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: c.podAdd,
+			UpdateFunc: func(_, new interface{}) {
+				c.podAdd(new)
+			},
+		},
+	)
+	if err != nil {
+		if removeErr := c.nodeInformer.Informer().RemoveEventHandler(registration); removeErr != nil {
+			log.Printf("failed to remove node event handler: %v", removeErr)
+		}
 
-	endpoint, ok := node.Annotations[bmcEndpointAnnotation]
-	if !ok {
-		return
+		return err
 	}
 
-	user, ok := node.Annotations[bmcUserAnnotation]
-	if !ok {
+	go wait.Until(c.resync, resyncPeriod, stopCh)
+
+	go func() {
+		<-stopCh
+
+		if err := c.nodeInformer.Informer().RemoveEventHandler(registration); err != nil {
+			log.Printf("failed to remove node event handler: %v", err)
+		}
+
+		if err := c.podInformer.Informer().RemoveEventHandler(podRegistration); err != nil {
+			log.Printf("failed to remove pod event handler: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// resync reconsiders all nodes, as the index changes without them changing.
+func (c *NodeManager) resync() {
+	nodes, err := c.nodeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		log.Printf("failed to list nodes: %v", err)
+
 		return
 	}
 
-	pass, ok := node.Annotations[bmcPasswordAnnotation]
-	if !ok {
-		return
+	for _, node := range nodes {
+		c.nodeAdd(node)
 	}
+}
 
-	log.Printf("node %q has BMC annotations", node.Name)
+func (c *NodeManager) nodeAdd(obj interface{}) {
+	node := obj.(*v1.Node)
 
-	bmcInfo := &bmc.BMCInfo{Endpoint: endpoint, User: user, Pass: pass}
+	c.powerMu.Lock()
+	defer c.powerMu.Unlock()
 
-	client, err := bmc.NewClient(bmcInfo)
+	client, err := c.driverFor(node)
 	if err != nil {
-		log.Printf("failed to create IPMI client: %v\n", err)
-	}
-	defer client.Close()
+		log.Printf("failed to create power driver for %q: %v\n", node.Name, err)
 
-	index, err := c.wattTimeClient.Index()
-	if err != nil {
-		log.Printf("failed to get index: %v\n", err)
+		return
+	}
 
+	if client == nil {
 		return
 	}
+	defer client.Close()
 
-	podIsInQueueThatFits, err := podInQueueThatFits(c.clientset, index)
+	index, err := c.indexCache.Index()
 	if err != nil {
-		log.Printf("failed to determine if a pod is in the queue: %v", err)
+		log.Printf("failed to get index: %v\n", err)
 
 		return
 	}
@@ -91,31 +158,115 @@ func (c *NodeManager) nodeAdd(obj interface{}) {
 		return
 	}
 
-	if podIsInQueueThatFits {
+	now := time.Now()
+
+	if !isPoweredOn {
+		c.resetIdle(node.Name)
+
+		pending, err := c.podsToPowerOn(index)
+		if err != nil {
+			log.Printf("failed to determine if a pod is in the queue: %v", err)
+
+			return
+		}
+
+		if len(pending) == 0 {
+			return
+		}
+
 		log.Printf("pod(s) in queue that can fit node")
 
-		if isPoweredOn {
-			// Nothing to do.
+		if !c.canPowerOn(node, now) {
+			log.Printf("node %q was powered off less than %s ago, leaving it powered off", node.Name, c.args.Args().MinNodeDowntime.Duration)
+
 			return
 		}
 
-		if !isPoweredOn {
-			log.Printf("index is %d%%, powering on %q", index, node.Name)
+		poweredOff, err := c.poweredOffNodes()
+		if err != nil {
+			log.Printf("failed to list powered off nodes: %v", err)
 
-			err = client.PowerOn()
-			if err != nil {
-				log.Printf("failed to power on node %q", node.Name)
-			}
+			return
 		}
-	} else {
-		if isIdle(node) {
-			log.Printf("node %q is idle, powering off", node.Name)
 
-			err = client.PowerOff()
-			if err != nil {
-				log.Printf("failed to power off node %q", node.Name)
-			}
+		if !packPendingPods(pending, poweredOff).Has(node.Name) {
+			log.Printf("pending pod(s) do not need %q, leaving it powered off", node.Name)
+
+			return
 		}
+
+		log.Printf("index is %d%%, powering on %q", index, node.Name)
+
+		err = client.PowerOn()
+		if err != nil {
+			log.Printf("failed to power on node %q", node.Name)
+
+			return
+		}
+
+		err = c.recordPowerOn(node, now)
+		if err != nil {
+			log.Printf("failed to record power on of node %q: %v", node.Name, err)
+		}
+
+		return
+	}
+
+	// Graceful drivers report the node as on until it halts, so it is left
+	// alone once powered off, the power cycle being counted only once. A node
+	// still on after shutdownTimeout may be powered off again.
+	if readPowerState(node).poweringOff(now) || c.isPoweringOff(node) {
+		c.resetIdle(node.Name)
+
+		return
+	}
+
+	pending, err := c.podsToStayOn(index)
+	if err != nil {
+		log.Printf("failed to determine if a pod is in the queue: %v", err)
+
+		return
+	}
+
+	if len(pending) > 0 {
+		// Nothing to do, and the node is not idle while pods wait for room.
+		c.resetIdle(node.Name)
+
+		return
+	}
+
+	if !c.isIdle(node) {
+		return
+	}
+
+	if c.isProtected(node) {
+		log.Printf("node %q is idle but protected, leaving it powered on", node.Name)
+
+		return
+	}
+
+	if !c.canPowerOff(node, now) {
+		log.Printf("node %q is idle but was powered on too recently or too often, leaving it powered on", node.Name)
+
+		return
+	}
+
+	log.Printf("node %q is idle, powering off", node.Name)
+
+	err = client.PowerOff()
+	if err != nil {
+		log.Printf("failed to power off node %q", node.Name)
+
+		return
+	}
+
+	c.mu.Lock()
+	c.poweredOff[node.Name] = now
+	c.mu.Unlock()
+
+	err = c.recordPowerOff(node, now)
+	if err != nil {
+		log.Printf("failed to record power off of node %q: %v", node.Name, err)
 	}
 }
 
@@ -125,28 +276,45 @@ func (c *NodeManager) nodeUpdate(old, new interface{}) {
 }
 
 func (c *NodeManager) nodeDelete(obj interface{}) {
-	node := obj.(*v1.Node)
+	node, ok := obj.(*v1.Node)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+
+		node, ok = tombstone.Obj.(*v1.Node)
+		if !ok {
+			return
+		}
+	}
+
+	c.mu.Lock()
+	delete(c.emptySince, node.Name)
+	delete(c.poweredOff, node.Name)
+	c.mu.Unlock()
 
 	klog.Infof("node deleted: %q", node.Name)
 }
 
-// NewNodeManager creates a NodeController.
-func NewNodeManager(informerFactory informers.SharedInformerFactory, clientset *kubernetes.Clientset, wattTimeClient *watttime.Client) (*NodeManager, error) {
+// NewNodeManager creates a NodeController. It must be created before the
+// informer factory is started.
+func NewNodeManager(informerFactory informers.SharedInformerFactory, clientset kubernetes.Interface, indexCache *energy.Cache, source *dynamicconfig.Source) (*NodeManager, error) {
 	nodeInformer := informerFactory.Core().V1().Nodes()
+	podInformer := informerFactory.Core().V1().Pods()
 
 	c := &NodeManager{
-		informerFactory: informerFactory,
-		nodeInformer:    nodeInformer,
-		clientset:       clientset,
-		wattTimeClient:  wattTimeClient,
+		nodeInformer: nodeInformer,
+		podInformer:  podInformer,
+		clientset:    clientset,
+		indexCache:   indexCache,
+		args:         source,
+		emptySince:   map[string]time.Time{},
+		poweredOff:   map[string]time.Time{},
+		profileNames: sets.New[string](),
 	}
-	_, err := nodeInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc:    c.nodeAdd,
-			UpdateFunc: c.nodeUpdate,
-			DeleteFunc: c.nodeDelete,
-		},
-	)
+
+	err := podInformer.Informer().AddIndexers(cache.Indexers{nodeNameIndex: indexPodByNodeName})
 	if err != nil {
 		return nil, err
 	}
@@ -154,27 +322,198 @@ func NewNodeManager(informerFactory informers.SharedInformerFactory, clientset *
 	return c, nil
 }
 
-func isIdle(node *v1.Node) bool {
-	return node.Status.Allocatable.Pods().Equal(*node.Status.Capacity.Pods())
+// AddProfile makes the pending pods of the scheduler profile power nodes on.
+func (c *NodeManager) AddProfile(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.profileNames.Insert(name)
+}
+
+// schedulerNames returns the schedulers whose pending pods power nodes on,
+// empty for all of them.
+func (c *NodeManager) schedulerNames() sets.Set[string] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.profileNames.Union(sets.New(c.args.Args().SchedulerNames...))
 }
 
-func podInQueueThatFits(clientset *kubernetes.Clientset, index int) (bool, error) {
-	pods, err := clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+// isIdle reports whether the node has had no workload pods assigned for at
+// least the configured idle duration. Pods in ignored namespaces are left out
+// here rather than in the index, as the namespaces may change at runtime.
+func (c *NodeManager) isIdle(node *v1.Node) bool {
+	objs, err := c.podInformer.Informer().GetIndexer().ByIndex(nodeNameIndex, node.Name)
 	if err != nil {
-		return false, err
+		log.Printf("failed to list pods on node %q: %v", node.Name, err)
+
+		return false
+	}
+
+	args := c.args.Args()
+	ignored := sets.New(args.IdleIgnoredNamespaces...)
+
+	busy := false
+
+	for _, obj := range objs {
+		if !ignored.Has(obj.(*v1.Pod).Namespace) {
+			busy = true
+
+			break
+		}
+	}
+
+	if busy {
+		c.resetIdle(node.Name)
+
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	since, ok := c.emptySince[node.Name]
+	if !ok {
+		since = time.Now()
+		c.emptySince[node.Name] = since
+	}
+
+	return time.Since(since) >= args.NodeIdleDuration.Duration
+}
+
+// resetIdle ends the idle period of the node, if any.
+func (c *NodeManager) resetIdle(nodeName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.emptySince, nodeName)
+}
+
+// podAdd ends the idle period of the node the pod is assigned to, unless the
+// pod does not keep nodes busy.
+func (c *NodeManager) podAdd(obj interface{}) {
+	nodeNames, _ := indexPodByNodeName(obj)
+	if len(nodeNames) == 0 {
+		return
+	}
+
+	ignored := sets.New(c.args.Args().IdleIgnoredNamespaces...)
+	if ignored.Has(obj.(*v1.Pod).Namespace) {
+		return
 	}
 
-	for _, pod := range pods.Items {
-		if pod.Spec.SchedulerName != "kube-scheduler-siderolabs" {
+	c.resetIdle(nodeNames[0])
+}
+
+// indexPodByNodeName indexes pods that keep a node busy by the node they are
+// assigned to. DaemonSet pods, mirror pods and finished pods run on every node
+// regardless of load, so they are left out.
+func indexPodByNodeName(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return nil, nil
+	}
+
+	if pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return nil, nil
+	}
+
+	if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return nil, nil
+	}
+
+	if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "DaemonSet" {
+		return nil, nil
+	}
+
+	return []string{pod.Spec.NodeName}, nil
+}
+
+// pendingPods returns the pods the scheduler found no room for that are
+// allowed to run at the given index. Pods not yet tried by the scheduler may
+// still fit on the nodes that are on.
+func (c *NodeManager) pendingPods(index int) ([]*v1.Pod, error) {
+	pods, err := c.podInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	schedulerNames := c.schedulerNames()
+
+	var pending []*v1.Pod
+
+	for _, pod := range pods {
+		if schedulerNames.Len() > 0 && !schedulerNames.Has(pod.Spec.SchedulerName) {
+			continue
+		}
+
+		if pod.Spec.NodeName != "" || pod.Status.Phase != v1.PodPending || !isUnschedulable(pod) {
 			continue
 		}
 
-		if pod.Status.Phase == v1.PodPending {
-			if pod.Spec.Priority != nil && *pod.Spec.Priority >= int32(index) {
-				return true, nil
-			}
+		if pod.Spec.Priority != nil && energy.Allows(*pod.Spec.Priority, index) {
+			pending = append(pending, pod)
+		}
+	}
+
+	return pending, nil
+}
+
+// podsToPowerOn returns the pending pods that justify powering a node on at
+// the given index. The index has to be further below their priority than
+// needed to keep a node on, so that an index hovering around a pod's priority
+// does not toggle nodes.
+func (c *NodeManager) podsToPowerOn(index int) ([]*v1.Pod, error) {
+	return c.pendingPods(index + int(c.args.Args().IndexHysteresis))
+}
+
+// podsToStayOn returns the pending pods that keep nodes powered on at the
+// given index.
+func (c *NodeManager) podsToStayOn(index int) ([]*v1.Pod, error) {
+	return c.pendingPods(index - int(c.args.Args().IndexHysteresis))
+}
+
+// isUnschedulable reports whether the scheduler tried and failed to place the
+// pod.
+func isUnschedulable(pod *v1.Pod) bool {
+	_, condition := podutil.GetPodCondition(&pod.Status, v1.PodScheduled)
+
+	return condition != nil && condition.Status == v1.ConditionFalse && condition.Reason == v1.PodReasonUnschedulable
+}
+
+// poweredOffNodes returns the power managed nodes that are not ready, using
+// their last known capacity as a stand-in for what they offer once on.
+func (c *NodeManager) poweredOffNodes() ([]*v1.Node, error) {
+	nodes, err := c.nodeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var poweredOff []*v1.Node
+
+	for _, node := range nodes {
+		if !power.IsManaged(node) {
+			continue
+		}
+
+		if !isReady(node) {
+			poweredOff = append(poweredOff, node)
+		}
+	}
+
+	return poweredOff, nil
+}
+
+func isReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
 		}
 	}
 
-	return false, nil
+	return false
 }
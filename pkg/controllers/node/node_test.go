@@ -0,0 +1,178 @@
+package node
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+)
+
+// newTestNodeManager returns a NodeManager whose informers hold the given
+// nodes and pods, without being started.
+func newTestNodeManager(t *testing.T, args *config.EmissionsArgs, nodes []*v1.Node, pods []*v1.Pod) *NodeManager {
+	t.Helper()
+
+	clientset := fake.NewSimpleClientset()
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+
+	c, err := NewNodeManager(factory, clientset, nil, dynamicconfig.NewSource(args))
+	if err != nil {
+		t.Fatalf("NewNodeManager() failed: %v", err)
+	}
+
+	for _, node := range nodes {
+		if err := c.nodeInformer.Informer().GetIndexer().Add(node); err != nil {
+			t.Fatalf("failed to add node %q: %v", node.Name, err)
+		}
+	}
+
+	for _, pod := range pods {
+		if err := c.podInformer.Informer().GetIndexer().Add(pod); err != nil {
+			t.Fatalf("failed to add pod %q: %v", pod.Name, err)
+		}
+	}
+
+	return c
+}
+
+// testPod returns a pending pod of the given priority that the scheduler
+// found no room for.
+func testPod(name string, priority int32) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{
+			SchedulerName: v1.DefaultSchedulerName,
+			Priority:      &priority,
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			Conditions: []v1.PodCondition{{
+				Type:   v1.PodScheduled,
+				Status: v1.ConditionFalse,
+				Reason: v1.PodReasonUnschedulable,
+			}},
+		},
+	}
+}
+
+// withRequests sets the CPU and memory requests of the pod.
+func withRequests(pod *v1.Pod, cpu, memory string) *v1.Pod {
+	pod.Spec.Containers = []v1.Container{{
+		Name: "main",
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}}
+
+	return pod
+}
+
+// testNode returns a node with the given allocatable CPU and memory.
+func testNode(name, cpu, memory string, ready bool) *v1.Node {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}, Labels: map[string]string{}},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func TestPendingPods(t *testing.T) {
+	scheduled := testPod("scheduled", 100)
+	scheduled.Spec.NodeName = "node"
+
+	untried := testPod("untried", 100)
+	untried.Status.Conditions = nil
+
+	otherScheduler := testPod("other-scheduler", 100)
+	otherScheduler.Spec.SchedulerName = "other"
+
+	noPriority := testPod("no-priority", 0)
+	noPriority.Spec.Priority = nil
+
+	pods := []*v1.Pod{
+		testPod("high", 80),
+		testPod("equal", 50),
+		testPod("low", 20),
+		scheduled,
+		untried,
+		otherScheduler,
+		noPriority,
+	}
+
+	tests := []struct {
+		name           string
+		index          int
+		schedulerNames []string
+		profiles       []string
+		want           []string
+	}{
+		{
+			name:  "priority above the index",
+			index: 50,
+			want:  []string{"high", "other-scheduler"},
+		},
+		{
+			name:  "low index",
+			index: 10,
+			want:  []string{"equal", "high", "low", "other-scheduler"},
+		},
+		{
+			name:     "profiles only",
+			index:    50,
+			profiles: []string{v1.DefaultSchedulerName},
+			want:     []string{"high"},
+		},
+		{
+			name:           "profiles and scheduler names",
+			index:          50,
+			profiles:       []string{v1.DefaultSchedulerName},
+			schedulerNames: []string{"other"},
+			want:           []string{"high", "other-scheduler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestNodeManager(t, &config.EmissionsArgs{SchedulerNames: tt.schedulerNames}, nil, pods)
+
+			for _, profile := range tt.profiles {
+				c.AddProfile(profile)
+			}
+
+			pending, err := c.pendingPods(tt.index)
+			if err != nil {
+				t.Fatalf("pendingPods() failed: %v", err)
+			}
+
+			got := sets.New[string]()
+			for _, pod := range pending {
+				got.Insert(pod.Name)
+			}
+
+			if !got.Equal(sets.New(tt.want...)) {
+				t.Errorf("pendingPods(%d) = %v, want %v", tt.index, sets.List(got), tt.want)
+			}
+		})
+	}
+}
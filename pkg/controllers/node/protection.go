@@ -0,0 +1,90 @@
+package node
+
+import (
+	"log"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// protectedAnnotation opts a node out of being powered off.
+const protectedAnnotation = "power.siderolabs.com/protected"
+
+// isProtected reports whether powering off the node is not allowed, either
+// because it opted out or because its pool would drop below the minimum
+// number of online nodes.
+func (c *NodeManager) isProtected(node *v1.Node) bool {
+	if node.Annotations[protectedAnnotation] == "true" {
+		return true
+	}
+
+	args := c.args.Args()
+
+	if args.NodePoolLabel == "" {
+		return false
+	}
+
+	pool, ok := node.Labels[args.NodePoolLabel]
+	if !ok {
+		return false
+	}
+
+	minOnline := int32(0)
+
+	for _, p := range args.NodePools {
+		if p.Name == pool {
+			minOnline = p.MinOnlineNodes
+		}
+	}
+
+	if minOnline == 0 {
+		return false
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{args.NodePoolLabel: pool})
+
+	nodes, err := c.nodeInformer.Lister().List(selector)
+	if err != nil {
+		log.Printf("failed to list nodes in pool %q: %v", pool, err)
+
+		return true
+	}
+
+	online := int32(0)
+
+	for _, n := range nodes {
+		if n.Name == node.Name {
+			continue
+		}
+
+		if isHealthy(n) && !c.isPoweringOff(n) {
+			online++
+		}
+	}
+
+	return online < minOnline
+}
+
+// isHealthy reports whether the node is ready, schedulable and not on its way
+// down.
+func isHealthy(node *v1.Node) bool {
+	if !isReady(node) || node.Spec.Unschedulable {
+		return false
+	}
+
+	// A node that was just powered off stays ready until its kubelet stops
+	// reporting.
+	return !readPowerState(node).poweringOff(time.Now())
+}
+
+// isPoweringOff reports whether the node was powered off by us since it was
+// last powered on, less than shutdownTimeout ago, when the informer does not
+// show it yet.
+func (c *NodeManager) isPoweringOff(node *v1.Node) bool {
+	c.mu.Lock()
+	at, ok := c.poweredOff[node.Name]
+	c.mu.Unlock()
+
+	return ok && at.After(readPowerState(node).lastPowerOn) && time.Since(at) < shutdownTimeout
+}
@@ -0,0 +1,109 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// inPool returns a ready node in the given pool.
+func inPool(name, pool string) *v1.Node {
+	node := testNode(name, "4", "16Gi", true)
+	node.Labels["pool"] = pool
+
+	return node
+}
+
+func TestIsProtected(t *testing.T) {
+	annotated := inPool("annotated", "unlimited")
+	annotated.Annotations[protectedAnnotation] = "true"
+
+	cordoned := inPool("cordoned", "workers")
+	cordoned.Spec.Unschedulable = true
+
+	notReady := testNode("not-ready", "4", "16Gi", false)
+	notReady.Labels["pool"] = "workers"
+
+	shuttingDown := withPowerState(inPool("shutting-down", "workers"), time.Now().Add(-time.Hour), time.Now().Add(-time.Minute), 1, "")
+
+	tests := []struct {
+		name       string
+		node       *v1.Node
+		others     []*v1.Node
+		poweredOff []string
+		want       bool
+	}{
+		{
+			name: "protected annotation",
+			node: annotated,
+			want: true,
+		},
+		{
+			name: "no pool",
+			node: testNode("node", "4", "16Gi", true),
+		},
+		{
+			name: "pool without minimum",
+			node: inPool("node", "unlimited"),
+		},
+		{
+			name:   "pool at its minimum",
+			node:   inPool("node", "workers"),
+			others: []*v1.Node{inPool("other", "workers")},
+			want:   true,
+		},
+		{
+			name:   "pool above its minimum",
+			node:   inPool("node", "workers"),
+			others: []*v1.Node{inPool("other-1", "workers"), inPool("other-2", "workers")},
+		},
+		{
+			name:   "nodes of other pools are not counted",
+			node:   inPool("node", "workers"),
+			others: []*v1.Node{inPool("other-1", "workers"), inPool("other-2", "unlimited")},
+			want:   true,
+		},
+		{
+			name:   "unhealthy nodes are not counted",
+			node:   inPool("node", "workers"),
+			others: []*v1.Node{inPool("other", "workers"), cordoned, notReady},
+			want:   true,
+		},
+		{
+			name:   "nodes shutting down are not counted",
+			node:   inPool("node", "workers"),
+			others: []*v1.Node{inPool("other", "workers"), shuttingDown},
+			want:   true,
+		},
+		{
+			name:       "nodes just powered off are not counted",
+			node:       inPool("node", "workers"),
+			others:     []*v1.Node{inPool("other-1", "workers"), inPool("other-2", "workers")},
+			poweredOff: []string{"other-2"},
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestNodeManager(t, &config.EmissionsArgs{
+				NodePoolLabel: "pool",
+				NodePools: []config.NodePool{
+					{Name: "workers", MinOnlineNodes: 2},
+					{Name: "unlimited"},
+				},
+			}, append([]*v1.Node{tt.node}, tt.others...), nil)
+
+			for _, name := range tt.poweredOff {
+				c.poweredOff[name] = time.Now()
+			}
+
+			if got := c.isProtected(tt.node); got != tt.want {
+				t.Errorf("isProtected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/siderolabs/kube-scheduler/pkg/agent"
+)
+
+// Power transitions are recorded on the node so that they survive scheduler
+// restarts.
+const lastPowerOnAnnotation = "power.siderolabs.com/last-power-on"
+const lastPowerOffAnnotation = "power.siderolabs.com/last-power-off"
+const powerCyclesAnnotation = "power.siderolabs.com/power-cycles"
+const powerCyclesDayAnnotation = "power.siderolabs.com/power-cycles-day"
+
+const dayLayout = "2006-01-02"
+
+// shutdownTimeout is how long a node is taken as powering off, after which
+// the shutdown is taken as failed, such as when no power agent handled it,
+// and the node may be powered off again.
+const shutdownTimeout = 10 * time.Minute
+
+// powerState is the power history of a node.
+type powerState struct {
+	lastPowerOn  time.Time
+	lastPowerOff time.Time
+	// cycles is the number of times the node was powered off on cyclesDay.
+	cycles    int
+	cyclesDay string
+}
+
+func readPowerState(node *v1.Node) powerState {
+	state := powerState{
+		lastPowerOn:  parseTime(node.Annotations[lastPowerOnAnnotation]),
+		lastPowerOff: parseTime(node.Annotations[lastPowerOffAnnotation]),
+		cyclesDay:    node.Annotations[powerCyclesDayAnnotation],
+	}
+
+	state.cycles, _ = strconv.Atoi(node.Annotations[powerCyclesAnnotation])
+
+	// Nodes have been up at least since they last became ready, which also
+	// covers nodes that were never powered on by us or were powered on
+	// outside of our control.
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue && condition.LastTransitionTime.After(state.lastPowerOn) {
+			state.lastPowerOn = condition.LastTransitionTime.Time
+		}
+	}
+
+	return state
+}
+
+// poweringOff reports whether the node was powered off since it was last
+// powered on, less than shutdownTimeout before now, its shutdown possibly
+// still being in progress.
+func (s powerState) poweringOff(now time.Time) bool {
+	return s.lastPowerOff.After(s.lastPowerOn) && now.Sub(s.lastPowerOff) < shutdownTimeout
+}
+
+// cyclesOn returns the number of power cycles on the day of t.
+func (s powerState) cyclesOn(t time.Time) int {
+	if s.cyclesDay != t.UTC().Format(dayLayout) {
+		return 0
+	}
+
+	return s.cycles
+}
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// canPowerOn reports whether the node has been down for long enough.
+func (c *NodeManager) canPowerOn(node *v1.Node, now time.Time) bool {
+	state := readPowerState(node)
+
+	if state.lastPowerOff.IsZero() {
+		return true
+	}
+
+	return now.Sub(state.lastPowerOff) >= c.args.Args().MinNodeDowntime.Duration
+}
+
+// canPowerOff reports whether the node has been up for long enough and has
+// not yet used up its power cycles for the day.
+func (c *NodeManager) canPowerOff(node *v1.Node, now time.Time) bool {
+	state := readPowerState(node)
+	args := c.args.Args()
+
+	if !state.lastPowerOn.IsZero() && now.Sub(state.lastPowerOn) < args.MinNodeUptime.Duration {
+		return false
+	}
+
+	if args.MaxPowerCyclesPerDay > 0 && state.cyclesOn(now) >= int(args.MaxPowerCyclesPerDay) {
+		return false
+	}
+
+	return true
+}
+
+// recordPowerOn stores the time the node was powered on, and clears the
+// shutdown request the power agent handled, if any. The node's idle period
+// starts over.
+func (c *NodeManager) recordPowerOn(node *v1.Node, now time.Time) error {
+	c.resetIdle(node.Name)
+
+	return c.patchAnnotations(node.Name, map[string]interface{}{
+		lastPowerOnAnnotation:    now.UTC().Format(time.RFC3339),
+		agent.ShutdownAnnotation: nil,
+	})
+}
+
+// recordPowerOff stores the time the node was powered off and counts the
+// power cycle. The node's idle period starts over.
+func (c *NodeManager) recordPowerOff(node *v1.Node, now time.Time) error {
+	c.resetIdle(node.Name)
+
+	state := readPowerState(node)
+
+	return c.patchAnnotations(node.Name, map[string]interface{}{
+		lastPowerOffAnnotation:   now.UTC().Format(time.RFC3339),
+		powerCyclesAnnotation:    strconv.Itoa(state.cyclesOn(now) + 1),
+		powerCyclesDayAnnotation: now.UTC().Format(dayLayout),
+	})
+}
+
+// patchAnnotations sets the annotations, removing those set to nil.
+func (c *NodeManager) patchAnnotations(nodeName string, annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.clientset.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
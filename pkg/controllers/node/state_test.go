@@ -0,0 +1,260 @@
+package node
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+var testNow = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+// withPowerState records the given power history on the node, leaving out
+// zero times and cycles.
+func withPowerState(node *v1.Node, lastPowerOn, lastPowerOff time.Time, cycles int, cyclesDay string) *v1.Node {
+	if !lastPowerOn.IsZero() {
+		node.Annotations[lastPowerOnAnnotation] = lastPowerOn.Format(time.RFC3339)
+	}
+
+	if !lastPowerOff.IsZero() {
+		node.Annotations[lastPowerOffAnnotation] = lastPowerOff.Format(time.RFC3339)
+	}
+
+	if cycles > 0 {
+		node.Annotations[powerCyclesAnnotation] = strconv.Itoa(cycles)
+		node.Annotations[powerCyclesDayAnnotation] = cyclesDay
+	}
+
+	return node
+}
+
+func TestReadPowerState(t *testing.T) {
+	readyAt := testNow.Add(-time.Hour)
+
+	node := withPowerState(testNode("node", "4", "16Gi", true), testNow.Add(-2*time.Hour), testNow.Add(-3*time.Hour), 2, "2026-03-15")
+	node.Status.Conditions[0].LastTransitionTime = metav1.NewTime(readyAt)
+
+	state := readPowerState(node)
+
+	if !state.lastPowerOn.Equal(readyAt) {
+		t.Errorf("lastPowerOn = %v, want the ready transition %v", state.lastPowerOn, readyAt)
+	}
+
+	if !state.lastPowerOff.Equal(testNow.Add(-3 * time.Hour)) {
+		t.Errorf("lastPowerOff = %v, want %v", state.lastPowerOff, testNow.Add(-3*time.Hour))
+	}
+
+	if state.cycles != 2 || state.cyclesDay != "2026-03-15" {
+		t.Errorf("cycles = %d on %q, want 2 on %q", state.cycles, state.cyclesDay, "2026-03-15")
+	}
+}
+
+func TestPoweringOff(t *testing.T) {
+	tests := []struct {
+		name  string
+		state powerState
+		want  bool
+	}{
+		{
+			name: "never powered off",
+			state: powerState{
+				lastPowerOn: testNow.Add(-time.Hour),
+			},
+		},
+		{
+			name: "powered on since",
+			state: powerState{
+				lastPowerOn:  testNow.Add(-time.Minute),
+				lastPowerOff: testNow.Add(-2 * time.Minute),
+			},
+		},
+		{
+			name: "shutdown in progress",
+			state: powerState{
+				lastPowerOn:  testNow.Add(-time.Hour),
+				lastPowerOff: testNow.Add(-time.Minute),
+			},
+			want: true,
+		},
+		{
+			name: "shutdown timed out",
+			state: powerState{
+				lastPowerOn:  testNow.Add(-time.Hour),
+				lastPowerOff: testNow.Add(-shutdownTimeout),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.poweringOff(testNow); got != tt.want {
+				t.Errorf("poweringOff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCyclesOn(t *testing.T) {
+	state := powerState{cycles: 3, cyclesDay: "2026-03-15"}
+
+	if got := state.cyclesOn(testNow); got != 3 {
+		t.Errorf("cyclesOn(same day) = %d, want 3", got)
+	}
+
+	if got := state.cyclesOn(testNow.Add(24 * time.Hour)); got != 0 {
+		t.Errorf("cyclesOn(next day) = %d, want 0", got)
+	}
+}
+
+func TestCanPowerOn(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastPowerOff time.Time
+		want         bool
+	}{
+		{
+			name: "never powered off",
+			want: true,
+		},
+		{
+			name:         "down for too short",
+			lastPowerOff: testNow.Add(-5 * time.Minute),
+		},
+		{
+			name:         "down for long enough",
+			lastPowerOff: testNow.Add(-10 * time.Minute),
+			want:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestNodeManager(t, &config.EmissionsArgs{
+				MinNodeDowntime: metav1.Duration{Duration: 10 * time.Minute},
+			}, nil, nil)
+
+			node := withPowerState(testNode("node", "4", "16Gi", false), time.Time{}, tt.lastPowerOff, 0, "")
+
+			if got := c.canPowerOn(node, testNow); got != tt.want {
+				t.Errorf("canPowerOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanPowerOff(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastPowerOn time.Time
+		cycles      int
+		cyclesDay   string
+		want        bool
+	}{
+		{
+			name: "never powered on",
+			want: true,
+		},
+		{
+			name:        "up for too short",
+			lastPowerOn: testNow.Add(-5 * time.Minute),
+		},
+		{
+			name:        "up for long enough",
+			lastPowerOn: testNow.Add(-time.Hour),
+			want:        true,
+		},
+		{
+			name:        "power cycles used up",
+			lastPowerOn: testNow.Add(-time.Hour),
+			cycles:      3,
+			cyclesDay:   "2026-03-15",
+		},
+		{
+			name:        "power cycles used up on another day",
+			lastPowerOn: testNow.Add(-time.Hour),
+			cycles:      3,
+			cyclesDay:   "2026-03-14",
+			want:        true,
+		},
+		{
+			name:        "power cycles left",
+			lastPowerOn: testNow.Add(-time.Hour),
+			cycles:      2,
+			cyclesDay:   "2026-03-15",
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestNodeManager(t, &config.EmissionsArgs{
+				MinNodeUptime:        metav1.Duration{Duration: 30 * time.Minute},
+				MaxPowerCyclesPerDay: 3,
+			}, nil, nil)
+
+			node := withPowerState(testNode("node", "4", "16Gi", false), tt.lastPowerOn, time.Time{}, tt.cycles, tt.cyclesDay)
+
+			if got := c.canPowerOff(node, testNow); got != tt.want {
+				t.Errorf("canPowerOff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHysteresis(t *testing.T) {
+	tests := []struct {
+		name          string
+		index         int
+		wantPowerOn   bool
+		wantStayingOn bool
+	}{
+		{
+			name:          "index well below the priority",
+			index:         35,
+			wantPowerOn:   true,
+			wantStayingOn: true,
+		},
+		{
+			name:          "index just below the priority",
+			index:         45,
+			wantStayingOn: true,
+		},
+		{
+			name:          "index just above the priority",
+			index:         55,
+			wantStayingOn: true,
+		},
+		{
+			name:  "index well above the priority",
+			index: 60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestNodeManager(t, &config.EmissionsArgs{IndexHysteresis: 10}, nil, []*v1.Pod{testPod("pod", 50)})
+
+			powerOn, err := c.podsToPowerOn(tt.index)
+			if err != nil {
+				t.Fatalf("podsToPowerOn() failed: %v", err)
+			}
+
+			if got := len(powerOn) > 0; got != tt.wantPowerOn {
+				t.Errorf("podsToPowerOn(%d) found pods = %v, want %v", tt.index, got, tt.wantPowerOn)
+			}
+
+			stayOn, err := c.podsToStayOn(tt.index)
+			if err != nil {
+				t.Fatalf("podsToStayOn() failed: %v", err)
+			}
+
+			if got := len(stayOn) > 0; got != tt.wantStayingOn {
+				t.Errorf("podsToStayOn(%d) found pods = %v, want %v", tt.index, got, tt.wantStayingOn)
+			}
+		})
+	}
+}
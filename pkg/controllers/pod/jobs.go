@@ -0,0 +1,196 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// suspendedPriorityAnnotation marks Jobs and CronJobs suspended because of
+// a high index. The value is the priority of their pods, used to decide when
+// to resume them.
+const suspendedPriorityAnnotation = "carbon.siderolabs.com/suspended-priority"
+
+// suspendJob suspends the Job owning the pod, and its CronJob if any,
+// instead of evicting the pod.
+func (c *PodManager) suspendJob(pod *v1.Pod, ref *metav1.OwnerReference) error {
+	job, err := c.jobInformer.Lister().Jobs(pod.Namespace).Get(ref.Name)
+	if err != nil {
+		return err
+	}
+
+	if cronRef := metav1.GetControllerOf(job); cronRef != nil && cronRef.Kind == "CronJob" {
+		cronJob, err := c.cronJobInformer.Lister().CronJobs(job.Namespace).Get(cronRef.Name)
+		if err != nil {
+			return err
+		}
+
+		if err = c.suspendCronJob(cronJob, *pod.Spec.Priority); err != nil {
+			return err
+		}
+	}
+
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return nil
+	}
+
+	log.Printf("suspending job %s/%s of pod %s", job.Namespace, job.Name, pod.Name)
+
+	patch, err := suspendPatch(true, strconv.Itoa(int(*pod.Spec.Priority)))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.clientset.BatchV1().Jobs(job.Namespace).Patch(context.TODO(), job.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
+
+func (c *PodManager) suspendCronJob(cronJob *batchv1.CronJob, priority int32) error {
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		return nil
+	}
+
+	log.Printf("suspending cronjob %s/%s", cronJob.Namespace, cronJob.Name)
+
+	patch, err := suspendPatch(true, strconv.Itoa(int(priority)))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.clientset.BatchV1().CronJobs(cronJob.Namespace).Patch(context.TODO(), cronJob.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
+
+// reconcileJobs suspends CronJobs whose pods would not be allowed to run at
+// the current index, so that their runs are skipped, and resumes the Jobs and
+// CronJobs we suspended once the index drops below their priority.
+func (c *PodManager) reconcileJobs() {
+	index, err := c.indexCache.Index()
+	if err != nil {
+		log.Printf("failed to get index: %v", err)
+
+		return
+	}
+
+	resume, err := suspendPatch(false, nil)
+	if err != nil {
+		return
+	}
+
+	jobs, err := c.jobInformer.Lister().List(labels.Everything())
+	if err != nil {
+		log.Printf("failed to list jobs: %v", err)
+
+		return
+	}
+
+	for _, job := range jobs {
+		priority, ok := suspendedPriority(job.Annotations)
+		if !ok || priority <= int32(index) {
+			continue
+		}
+
+		log.Printf("resuming job %s/%s, priority is %d, index is %d", job.Namespace, job.Name, priority, index)
+
+		_, err = c.clientset.BatchV1().Jobs(job.Namespace).Patch(context.TODO(), job.Name, types.MergePatchType, resume, metav1.PatchOptions{})
+		if err != nil {
+			log.Printf("failed to resume job %s/%s: %v", job.Namespace, job.Name, err)
+		}
+	}
+
+	cronJobs, err := c.cronJobInformer.Lister().List(labels.Everything())
+	if err != nil {
+		log.Printf("failed to list cronjobs: %v", err)
+
+		return
+	}
+
+	for _, cronJob := range cronJobs {
+		if priority, ok := suspendedPriority(cronJob.Annotations); ok {
+			if priority <= int32(index) {
+				continue
+			}
+
+			log.Printf("resuming cronjob %s/%s, priority is %d, index is %d", cronJob.Namespace, cronJob.Name, priority, index)
+
+			_, err = c.clientset.BatchV1().CronJobs(cronJob.Namespace).Patch(context.TODO(), cronJob.Name, types.MergePatchType, resume, metav1.PatchOptions{})
+			if err != nil {
+				log.Printf("failed to resume cronjob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+			}
+
+			continue
+		}
+
+		priority := c.templatePriority(&cronJob.Spec.JobTemplate.Spec.Template.Spec)
+		if priority >= int32(index) || c.confirmationRemaining(priority) > 0 {
+			continue
+		}
+
+		if err = c.suspendCronJob(cronJob, priority); err != nil {
+			log.Printf("failed to suspend cronjob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+		}
+	}
+}
+
+// templatePriority resolves the priority pods created from the spec will
+// get.
+func (c *PodManager) templatePriority(spec *v1.PodSpec) int32 {
+	if spec.Priority != nil {
+		return *spec.Priority
+	}
+
+	classes, err := c.priorityClassInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return 0
+	}
+
+	for _, class := range classes {
+		if spec.PriorityClassName != "" && class.Name == spec.PriorityClassName {
+			return class.Value
+		}
+
+		if spec.PriorityClassName == "" && class.GlobalDefault {
+			return class.Value
+		}
+	}
+
+	return 0
+}
+
+func suspendedPriority(annotations map[string]string) (int32, bool) {
+	value, ok := annotations[suspendedPriorityAnnotation]
+	if !ok {
+		return 0, false
+	}
+
+	priority, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return int32(priority), true
+}
+
+// suspendPatch sets spec.suspend and the suspended priority annotation; a nil
+// priority removes the annotation.
+func suspendPatch(suspend bool, priority interface{}) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				suspendedPriorityAnnotation: priority,
+			},
+		},
+		"spec": map[string]interface{}{
+			"suspend": suspend,
+		},
+	})
+}
@@ -4,95 +4,561 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
-	"github.com/siderolabs/kube-scheduler/pkg/energy/watttime"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	schedulinginformers "k8s.io/client-go/informers/scheduling/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 	klog "k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
 
+const controllerUIDIndex = "controllerUID"
+
+// evictionTTL is how long an eviction is counted against its workload when
+// the pod is not seen terminating.
+const evictionTTL = 5 * time.Minute
+
+// resyncPeriod is how often all pods are reconsidered.
+const resyncPeriod = 5 * time.Minute
+
+// workloadRetryDelay is how long to wait before retrying a pod whose workload
+// already has too many replicas being evicted.
+const workloadRetryDelay = 30 * time.Second
+
 // PodManager manages the power state of pods.
 type PodManager struct {
-	informerFactory informers.SharedInformerFactory
-	podInformer     coreinformers.PodInformer
-	clientset       *kubernetes.Clientset
-	wattTimeClient  *watttime.Client
+	podInformer coreinformers.PodInformer
+	clientset   kubernetes.Interface
+	indexCache  *energy.Cache
+	args        *dynamicconfig.Source
+
+	// Only set when SuspendJobs is enabled.
+	jobInformer     batchinformers.JobInformer
+	cronJobInformer batchinformers.CronJobInformer
+
+	// Only set when ScaleWorkloads is enabled.
+	deploymentInformer  appsinformers.DeploymentInformer
+	replicaSetInformer  appsinformers.ReplicaSetInformer
+	statefulSetInformer appsinformers.StatefulSetInformer
+
+	// Only set when SuspendJobs or ScaleWorkloads is enabled.
+	priorityClassInformer schedulinginformers.PriorityClassInformer
+
+	mu sync.Mutex
+	// clusterLimiter limits evictions across the cluster to
+	// clusterLimiterRate per minute.
+	clusterLimiter     *rate.Limiter
+	clusterLimiterRate int32
+	// namespaceLimiters limits evictions per namespace to
+	// namespaceLimitersRate per minute.
+	namespaceLimiters     map[string]*rate.Limiter
+	namespaceLimitersRate int32
+	// evicted records the pods evicted and when, until they are deleted.
+	evicted map[types.UID]time.Time
 }
 
-// Run starts shared informers and waits for the shared informer cache to
-// synchronize.
+// Run waits for the shared informer cache to synchronize and manages pods
+// until stopCh is closed. The informer factory is started by the caller, and
+// a PodManager may be run again once stopped.
 func (c *PodManager) Run(stopCh <-chan struct{}) error {
-	c.informerFactory.Start(stopCh)
-	if !cache.WaitForCacheSync(stopCh, c.podInformer.Informer().HasSynced) {
+	synced := []cache.InformerSynced{c.podInformer.Informer().HasSynced}
+
+	if c.args.Args().SuspendJobs {
+		synced = append(synced, c.jobInformer.Informer().HasSynced, c.cronJobInformer.Informer().HasSynced)
+	}
+
+	if c.args.Args().ScaleWorkloads {
+		synced = append(synced,
+			c.deploymentInformer.Informer().HasSynced,
+			c.replicaSetInformer.Informer().HasSynced,
+			c.statefulSetInformer.Informer().HasSynced,
+		)
+	}
+
+	if c.priorityClassInformer != nil {
+		synced = append(synced, c.priorityClassInformer.Informer().HasSynced)
+	}
+
+	if !cache.WaitForCacheSync(stopCh, synced...) {
 		return fmt.Errorf("failed to sync")
 	}
 
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "pods")
+
+	registration, err := c.podInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.podAdd(queue, obj)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				c.podUpdate(queue, old, new)
+			},
+			DeleteFunc: c.podDelete,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	go wait.Until(func() { c.runWorker(queue) }, time.Second, stopCh)
+	go wait.Until(func() { c.resync(queue) }, resyncPeriod, stopCh)
+
+	if c.args.Args().SuspendJobs {
+		go wait.Until(c.reconcileJobs, c.indexCache.Interval(), stopCh)
+	}
+
+	if c.args.Args().ScaleWorkloads {
+		go wait.Until(c.reconcileWorkloads, c.indexCache.Interval(), stopCh)
+	}
+
+	go func() {
+		<-stopCh
+
+		if err := c.podInformer.Informer().RemoveEventHandler(registration); err != nil {
+			log.Printf("failed to remove pod event handler: %v", err)
+		}
+
+		queue.ShutDown()
+	}()
+
 	return nil
 }
 
-func (c *PodManager) podAdd(obj interface{}) {
+// resync reconsiders all pods, as the index changes without them changing.
+func (c *PodManager) resync(queue workqueue.RateLimitingInterface) {
+	pods, err := c.podInformer.Lister().List(labels.Everything())
+	if err != nil {
+		log.Printf("failed to list pods: %v", err)
+
+		return
+	}
+
+	for _, pod := range pods {
+		c.podAdd(queue, pod)
+	}
+}
+
+func (c *PodManager) podAdd(queue workqueue.RateLimitingInterface, obj interface{}) {
 	pod := obj.(*v1.Pod)
 
+	if pod.Status.Phase == v1.PodPending || pod.DeletionTimestamp != nil {
+		return
+	}
+
+	key, err := cache.MetaNamespaceKeyFunc(pod)
+	if err != nil {
+		return
+	}
+
+	queue.Add(key)
+}
+
+func (c *PodManager) podUpdate(queue workqueue.RateLimitingInterface, old, new interface{}) {
+	oldPod := old.(*v1.Pod)
+	newPod := new.(*v1.Pod)
+
+	// Pods preempted by any scheduler replica count as evictions.
+	if preemptedByScheduler(newPod) && !preemptedByScheduler(oldPod) {
+		c.recordPreemption(newPod)
+	}
+
+	c.podAdd(queue, newPod)
+}
+
+// preemptedByScheduler reports whether the scheduler marked the pod as a
+// preemption victim.
+func preemptedByScheduler(pod *v1.Pod) bool {
+	_, condition := podutil.GetPodCondition(&pod.Status, v1.DisruptionTarget)
+
+	return condition != nil && condition.Status == v1.ConditionTrue && condition.Reason == v1.PodReasonPreemptionByScheduler
+}
+
+func (c *PodManager) podDelete(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+
+		pod, ok = tombstone.Obj.(*v1.Pod)
+		if !ok {
+			return
+		}
+	}
+
+	c.mu.Lock()
+	delete(c.evicted, pod.UID)
+	c.mu.Unlock()
+
+	klog.Infof("pod deleted: %q", pod.Name)
+}
+
+func (c *PodManager) runWorker(queue workqueue.RateLimitingInterface) {
+	for c.processNextItem(queue) {
+	}
+}
+
+func (c *PodManager) processNextItem(queue workqueue.RateLimitingInterface) bool {
+	item, quit := queue.Get()
+	if quit {
+		return false
+	}
+	defer queue.Done(item)
+
+	key := item.(string)
+
+	requeue, err := c.sync(key)
+
+	switch {
+	case err != nil:
+		log.Printf("failed to evict pod %s: %v", key, err)
+
+		queue.AddRateLimited(key)
+	case requeue > 0:
+		queue.Forget(key)
+		queue.AddAfter(key, requeue)
+	case requeue < 0:
+		queue.AddRateLimited(key)
+	default:
+		queue.Forget(key)
+	}
+
+	return true
+}
+
+// sync evicts the pod if its priority is below the current index. It returns
+// a positive delay to retry after, or a negative one to retry with backoff.
+func (c *PodManager) sync(key string) (time.Duration, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, nil
+	}
+
+	pod, err := c.podInformer.Lister().Pods(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	// Already on its way out, possibly because we evicted it.
+	if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodPending {
+		return 0, nil
+	}
+
 	if pod.Spec.Priority == nil {
 		log.Printf("priority not set on pod %s/%s", pod.Namespace, pod.Name)
 
-		return
+		return 0, nil
 	}
 
-	index, err := c.wattTimeClient.Index()
+	index, err := c.indexCache.Index()
 	if err != nil {
-		log.Printf("failed to get index: %v\n", err)
+		return 0, fmt.Errorf("failed to get index: %w", err)
+	}
 
-		return
+	if *pod.Spec.Priority >= int32(index) {
+		return 0, nil
 	}
 
 	log.Printf("pod (%s/%s) priority is %d, index is %d", pod.Namespace, pod.Name, *pod.Spec.Priority, index)
 
-	if *pod.Spec.Priority < int32(index) && pod.Status.Phase != v1.PodPending {
-		err = c.clientset.PolicyV1().Evictions(pod.Namespace).Evict(context.TODO(), &policy.Eviction{ObjectMeta: pod.ObjectMeta})
-		if err != nil {
-			log.Printf("failed to evict pod %q: %v\n", pod.Name, err)
+	if remaining := c.confirmationRemaining(*pod.Spec.Priority); remaining > 0 {
+		log.Printf("waiting %s for the index to be confirmed above the priority of pod %s/%s", remaining, pod.Namespace, pod.Name)
+
+		return remaining, nil
+	}
+
+	if c.args.Args().ScaleWorkloads && c.isScaledWorkload(pod) {
+		// Scaled down by reconcileWorkloads.
+		return 0, nil
+	}
+
+	if ref := metav1.GetControllerOf(pod); c.args.Args().SuspendJobs && ref != nil && ref.Kind == "Job" {
+		return 0, c.suspendJob(pod, ref)
+	}
+
+	if !c.workloadAllowsEviction(pod) {
+		log.Printf("too many replicas of the workload of pod %s/%s are being evicted, retrying later", pod.Namespace, pod.Name)
+
+		return workloadRetryDelay, nil
+	}
+
+	if !c.rateLimitAllowsEviction(pod.Namespace) {
+		return -1, nil
+	}
+
+	eviction := &policy.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+
+	if grace := c.args.Args().EvictionGracePeriodSeconds; grace != nil {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: grace}
+	}
+
+	err = c.clientset.PolicyV1().Evictions(pod.Namespace).Evict(context.TODO(), eviction)
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return 0, nil
+	case apierrors.IsTooManyRequests(err):
+		// The eviction would violate a PodDisruptionBudget.
+		log.Printf("eviction of pod %s/%s blocked by disruption budget, retrying later", pod.Namespace, pod.Name)
+
+		return -1, nil
+	case err != nil:
+		return 0, err
+	}
+
+	c.recordEviction(pod)
+
+	log.Printf("evicted pod %s/%s", pod.Namespace, pod.Name)
+
+	return 0, nil
+}
+
+// confirmationRemaining returns how much longer the index has to stay above
+// the priority before the pod is evicted, so that a single spike does not
+// trigger evictions.
+func (c *PodManager) confirmationRemaining(priority int32) time.Duration {
+	count, since := c.indexCache.AboveSince(int(priority))
+	args := c.args.Args()
+
+	var remaining time.Duration
+
+	if samples := int(args.EvictionConfirmationSamples); count < samples {
+		remaining = time.Duration(samples-count) * c.indexCache.Interval()
+	}
+
+	if period := args.EvictionConfirmationPeriod.Duration; period > 0 {
+		if elapsed := time.Since(since); elapsed < period && period-elapsed > remaining {
+			remaining = period - elapsed
 		}
+	}
 
-		log.Printf("evicted pod %s/%s", pod.Namespace, pod.Name)
+	return remaining
+}
 
-		return
+// rateLimitAllowsEviction takes a token from the namespace and cluster-wide
+// eviction rate limiters. Tokens are only taken when both limiters allow the
+// eviction, so that a namespace does not use up its budget on evictions the
+// cluster-wide limiter rejects.
+func (c *PodManager) rateLimitAllowsEviction(namespace string) bool {
+	now := time.Now()
+
+	var reservations []*rate.Reservation
+
+	for _, limiter := range c.limiters(namespace) {
+		reservation := limiter.ReserveN(now, 1)
+		if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+			reservation.CancelAt(now)
+
+			for _, r := range reservations {
+				r.CancelAt(now)
+			}
+
+			return false
+		}
+
+		reservations = append(reservations, reservation)
 	}
+
+	return true
 }
 
-func (c *PodManager) podUpdate(old, new interface{}) {
-	newPod := new.(*v1.Pod)
-	c.podAdd(newPod)
+// limiters returns the eviction rate limiters that apply to the namespace.
+func (c *PodManager) limiters(namespace string) []*rate.Limiter {
+	var limiters []*rate.Limiter
+
+	if limiter := c.namespaceLimiter(namespace); limiter != nil {
+		limiters = append(limiters, limiter)
+	}
+
+	if limiter := c.getClusterLimiter(); limiter != nil {
+		limiters = append(limiters, limiter)
+	}
+
+	return limiters
 }
 
-func (c *PodManager) podDelete(obj interface{}) {
-	pod := obj.(*v1.Pod)
+func (c *PodManager) namespaceLimiter(namespace string) *rate.Limiter {
+	perMinute := c.args.Args().MaxNamespaceEvictionsPerMinute
+	if perMinute <= 0 {
+		return nil
+	}
 
-	klog.Infof("pod deleted: %q", pod.Name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The limit may have changed at runtime.
+	if perMinute != c.namespaceLimitersRate {
+		c.namespaceLimiters = map[string]*rate.Limiter{}
+		c.namespaceLimitersRate = perMinute
+	}
+
+	limiter, ok := c.namespaceLimiters[namespace]
+	if !ok {
+		limiter = newPerMinuteLimiter(perMinute)
+		c.namespaceLimiters[namespace] = limiter
+	}
+
+	return limiter
 }
 
-// NewPodManager creates a PodManager.
-func NewPodManager(informerFactory informers.SharedInformerFactory, clientset *kubernetes.Clientset, wattTimeClient *watttime.Client) (*PodManager, error) {
+func (c *PodManager) getClusterLimiter() *rate.Limiter {
+	perMinute := c.args.Args().MaxEvictionsPerMinute
+	if perMinute <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The limit may have changed at runtime.
+	if c.clusterLimiter == nil || perMinute != c.clusterLimiterRate {
+		c.clusterLimiter = newPerMinuteLimiter(perMinute)
+		c.clusterLimiterRate = perMinute
+	}
+
+	return c.clusterLimiter
+}
+
+// workloadAllowsEviction reports whether evicting the pod keeps the share of
+// its workload's replicas being evicted within the configured maximum.
+func (c *PodManager) workloadAllowsEviction(pod *v1.Pod) bool {
+	percent := c.args.Args().MaxEvictedReplicasPercent
+	if percent <= 0 {
+		return true
+	}
+
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return true
+	}
+
+	objs, err := c.podInformer.Informer().GetIndexer().ByIndex(controllerUIDIndex, string(ref.UID))
+	if err != nil {
+		log.Printf("failed to list pods of %s %s/%s: %v", ref.Kind, pod.Namespace, ref.Name, err)
+
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	evicting := 0
+
+	for _, obj := range objs {
+		p := obj.(*v1.Pod)
+
+		if p.DeletionTimestamp != nil {
+			evicting++
+
+			continue
+		}
+
+		if at, ok := c.evicted[p.UID]; ok && time.Since(at) < evictionTTL {
+			evicting++
+		}
+	}
+
+	allowed := len(objs) * int(percent) / 100
+	if allowed < 1 {
+		allowed = 1
+	}
+
+	return evicting < allowed
+}
+
+// Evictable reports whether the pod may be evicted without exceeding the
+// eviction rate limits or the share of its workload's replicas being
+// evicted. No token is taken from the rate limiters.
+func (c *PodManager) Evictable(pod *v1.Pod) bool {
+	now := time.Now()
+
+	for _, limiter := range c.limiters(pod.Namespace) {
+		if limiter.TokensAt(now) < 1 {
+			return false
+		}
+	}
+
+	return c.workloadAllowsEviction(pod)
+}
+
+// recordPreemption accounts for a pod preempted by the scheduler as if it
+// was evicted, so that its workload and the eviction rate limits reflect it.
+// The preemption already happened, so its tokens are taken even if none are
+// left, delaying later evictions.
+func (c *PodManager) recordPreemption(pod *v1.Pod) {
+	now := time.Now()
+
+	for _, limiter := range c.limiters(pod.Namespace) {
+		limiter.ReserveN(now, 1)
+	}
+
+	c.recordEviction(pod)
+}
+
+func (c *PodManager) recordEviction(pod *v1.Pod) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evicted[pod.UID] = time.Now()
+}
+
+// NewPodManager creates a PodManager. It must be created before the informer
+// factory is started.
+func NewPodManager(informerFactory informers.SharedInformerFactory, clientset kubernetes.Interface, indexCache *energy.Cache, source *dynamicconfig.Source) (*PodManager, error) {
 	podInformer := informerFactory.Core().V1().Pods()
 
 	c := &PodManager{
-		informerFactory: informerFactory,
-		podInformer:     podInformer,
-		clientset:       clientset,
-		wattTimeClient:  wattTimeClient,
+		podInformer:       podInformer,
+		clientset:         clientset,
+		indexCache:        indexCache,
+		args:              source,
+		namespaceLimiters: map[string]*rate.Limiter{},
+		evicted:           map[types.UID]time.Time{},
 	}
-	_, err := podInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc:    c.podAdd,
-			UpdateFunc: c.podUpdate,
-			DeleteFunc: c.podDelete,
-		},
-	)
+
+	// The informers watched cannot change at runtime.
+	args := source.Args()
+
+	if args.SuspendJobs {
+		c.jobInformer = informerFactory.Batch().V1().Jobs()
+		c.cronJobInformer = informerFactory.Batch().V1().CronJobs()
+	}
+
+	if args.ScaleWorkloads {
+		c.deploymentInformer = informerFactory.Apps().V1().Deployments()
+		c.replicaSetInformer = informerFactory.Apps().V1().ReplicaSets()
+		c.statefulSetInformer = informerFactory.Apps().V1().StatefulSets()
+	}
+
+	if args.SuspendJobs || args.ScaleWorkloads {
+		c.priorityClassInformer = informerFactory.Scheduling().V1().PriorityClasses()
+	}
+
+	err := podInformer.Informer().AddIndexers(cache.Indexers{controllerUIDIndex: indexByControllerUID})
 	if err != nil {
 		return nil, err
 	}
@@ -100,22 +566,20 @@ func NewPodManager(informerFactory informers.SharedInformerFactory, clientset *k
 	return c, nil
 }
 
-func Run(clientset *kubernetes.Clientset, wattTimeClient *watttime.Client) {
-	factory := informers.NewSharedInformerFactory(clientset, (5*time.Minute)/2)
-	manager, err := NewPodManager(factory, clientset, wattTimeClient)
-	if err != nil {
-		klog.Fatal(err)
-	}
+func newPerMinuteLimiter(perMinute int32) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(float64(perMinute)/60), 1)
+}
 
-	stop := make(chan struct{})
-	defer close(stop)
+func indexByControllerUID(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return nil, nil
+	}
 
-	go func() {
-		err = manager.Run(stop)
-		if err != nil {
-			klog.Fatal(err)
-		}
-	}()
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return nil, nil
+	}
 
-	select {}
+	return []string{string(ref.UID)}, nil
 }
@@ -0,0 +1,203 @@
+package pod
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+)
+
+// newTestPodManager returns a PodManager whose pod informer holds the given
+// pods, without being started.
+func newTestPodManager(t *testing.T, args *config.EmissionsArgs, pods []*v1.Pod) *PodManager {
+	t.Helper()
+
+	clientset := fake.NewSimpleClientset()
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+
+	c, err := NewPodManager(factory, clientset, nil, dynamicconfig.NewSource(args))
+	if err != nil {
+		t.Fatalf("NewPodManager() failed: %v", err)
+	}
+
+	for _, pod := range pods {
+		if err := c.podInformer.Informer().GetIndexer().Add(pod); err != nil {
+			t.Fatalf("failed to add pod %q: %v", pod.Name, err)
+		}
+	}
+
+	return c
+}
+
+// replica returns a pod controlled by the ReplicaSet with the given UID.
+func replica(name string, controllerUID types.UID) *v1.Pod {
+	controller := true
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "rs",
+				UID:        controllerUID,
+				Controller: &controller,
+			}},
+		},
+	}
+}
+
+func TestRateLimitAllowsEviction(t *testing.T) {
+	c := newTestPodManager(t, &config.EmissionsArgs{
+		MaxEvictionsPerMinute:          1,
+		MaxNamespaceEvictionsPerMinute: 1,
+	}, nil)
+
+	if !c.rateLimitAllowsEviction("a") {
+		t.Fatalf("first eviction was rejected")
+	}
+
+	if c.rateLimitAllowsEviction("a") {
+		t.Errorf("second eviction in the same namespace was allowed")
+	}
+
+	if c.rateLimitAllowsEviction("b") {
+		t.Errorf("eviction over the cluster limit was allowed")
+	}
+
+	// The cluster-wide limiter rejected the eviction, so the namespace
+	// keeps its token.
+	if tokens := c.namespaceLimiter("b").TokensAt(time.Now()); tokens < 1 {
+		t.Errorf("namespace has %.2f tokens left, want 1", tokens)
+	}
+}
+
+func TestRateLimitAllowsEvictionUnlimited(t *testing.T) {
+	c := newTestPodManager(t, &config.EmissionsArgs{}, nil)
+
+	for i := 0; i < 10; i++ {
+		if !c.rateLimitAllowsEviction("a") {
+			t.Fatalf("eviction %d was rejected without limits", i)
+		}
+	}
+}
+
+func TestEvictable(t *testing.T) {
+	pod := replica("pod", "rs")
+
+	c := newTestPodManager(t, &config.EmissionsArgs{
+		MaxEvictionsPerMinute:          1,
+		MaxNamespaceEvictionsPerMinute: 1,
+	}, []*v1.Pod{pod})
+
+	// Evictable does not take tokens.
+	for i := 0; i < 2; i++ {
+		if !c.Evictable(pod) {
+			t.Fatalf("Evictable() = false on call %d, want true", i)
+		}
+	}
+
+	c.recordPreemption(pod)
+
+	if c.Evictable(pod) {
+		t.Errorf("Evictable() = true after the preemption used up the rate limit")
+	}
+}
+
+func TestWorkloadAllowsEviction(t *testing.T) {
+	deleting := replica("deleting", "rs")
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	orphan := replica("orphan", "")
+	orphan.OwnerReferences = nil
+
+	tests := []struct {
+		name    string
+		percent int32
+		pod     *v1.Pod
+		pods    []*v1.Pod
+		evicted map[types.UID]time.Duration
+		want    bool
+	}{
+		{
+			name:    "no limit",
+			pod:     replica("pod", "rs"),
+			pods:    []*v1.Pod{replica("pod", "rs"), deleting},
+			evicted: map[types.UID]time.Duration{"other": 0},
+			want:    true,
+		},
+		{
+			name:    "no controller",
+			percent: 50,
+			pod:     orphan,
+			pods:    []*v1.Pod{orphan},
+			want:    true,
+		},
+		{
+			name:    "none being evicted",
+			percent: 50,
+			pod:     replica("pod", "rs"),
+			pods:    []*v1.Pod{replica("pod", "rs"), replica("a", "rs"), replica("b", "rs"), replica("c", "rs")},
+			want:    true,
+		},
+		{
+			name:    "below the maximum",
+			percent: 50,
+			pod:     replica("pod", "rs"),
+			pods:    []*v1.Pod{replica("pod", "rs"), replica("a", "rs"), replica("b", "rs"), deleting},
+			want:    true,
+		},
+		{
+			name:    "at the maximum",
+			percent: 50,
+			pod:     replica("pod", "rs"),
+			pods:    []*v1.Pod{replica("pod", "rs"), replica("a", "rs"), replica("b", "rs"), deleting},
+			evicted: map[types.UID]time.Duration{"a": time.Minute},
+		},
+		{
+			name:    "expired evictions are not counted",
+			percent: 50,
+			pod:     replica("pod", "rs"),
+			pods:    []*v1.Pod{replica("pod", "rs"), replica("a", "rs"), replica("b", "rs"), deleting},
+			evicted: map[types.UID]time.Duration{"a": evictionTTL},
+			want:    true,
+		},
+		{
+			name:    "other workloads are not counted",
+			percent: 50,
+			pod:     replica("pod", "rs"),
+			pods:    []*v1.Pod{replica("pod", "rs"), replica("a", "rs"), replica("b", "other"), replica("c", "other")},
+			evicted: map[types.UID]time.Duration{"b": 0, "c": 0},
+			want:    true,
+		},
+		{
+			name:    "one replica at a time for small workloads",
+			percent: 10,
+			pod:     replica("pod", "rs"),
+			pods:    []*v1.Pod{replica("pod", "rs"), deleting},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestPodManager(t, &config.EmissionsArgs{MaxEvictedReplicasPercent: tt.percent}, tt.pods)
+
+			for uid, ago := range tt.evicted {
+				c.evicted[uid] = time.Now().Add(-ago)
+			}
+
+			if got := c.workloadAllowsEviction(tt.pod); got != tt.want {
+				t.Errorf("workloadAllowsEviction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
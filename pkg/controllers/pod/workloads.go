@@ -0,0 +1,210 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// scaleDownReplicasAnnotation opts a Deployment or StatefulSet into being
+// scaled to the given number of replicas while the index is above its pods'
+// priority, instead of having its pods evicted.
+const scaleDownReplicasAnnotation = "carbon.siderolabs.com/scale-down-replicas"
+
+// originalReplicasAnnotation records the replicas of a scaled down workload,
+// restored once the index drops.
+const originalReplicasAnnotation = "carbon.siderolabs.com/original-replicas"
+
+// scaledWorkload is the subset of a Deployment or StatefulSet needed to scale
+// it.
+type scaledWorkload struct {
+	kind     string
+	meta     *metav1.ObjectMeta
+	replicas *int32
+	template *v1.PodTemplateSpec
+	patchFn  func(patch []byte) error
+}
+
+// isScaledWorkload reports whether the pod belongs to a Deployment or
+// StatefulSet opted into scaling, whose pods are not evicted.
+func (c *PodManager) isScaledWorkload(pod *v1.Pod) bool {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return false
+	}
+
+	switch ref.Kind {
+	case "StatefulSet":
+		set, err := c.statefulSetInformer.Lister().StatefulSets(pod.Namespace).Get(ref.Name)
+		if err != nil {
+			return false
+		}
+
+		_, ok := set.Annotations[scaleDownReplicasAnnotation]
+
+		return ok
+	case "ReplicaSet":
+		rs, err := c.replicaSetInformer.Lister().ReplicaSets(pod.Namespace).Get(ref.Name)
+		if err != nil {
+			return false
+		}
+
+		rsRef := metav1.GetControllerOf(rs)
+		if rsRef == nil || rsRef.Kind != "Deployment" {
+			return false
+		}
+
+		deployment, err := c.deploymentInformer.Lister().Deployments(pod.Namespace).Get(rsRef.Name)
+		if err != nil {
+			return false
+		}
+
+		_, ok := deployment.Annotations[scaleDownReplicasAnnotation]
+
+		return ok
+	}
+
+	return false
+}
+
+// reconcileWorkloads scales opted-in Deployments and StatefulSets down while
+// the index is above their pods' priority and restores them afterwards.
+func (c *PodManager) reconcileWorkloads() {
+	index, err := c.indexCache.Index()
+	if err != nil {
+		log.Printf("failed to get index: %v", err)
+
+		return
+	}
+
+	var workloads []scaledWorkload
+
+	deployments, err := c.deploymentInformer.Lister().List(labels.Everything())
+	if err != nil {
+		log.Printf("failed to list deployments: %v", err)
+
+		return
+	}
+
+	for _, deployment := range deployments {
+		if _, ok := deployment.Annotations[scaleDownReplicasAnnotation]; !ok {
+			continue
+		}
+
+		client, name := c.clientset.AppsV1().Deployments(deployment.Namespace), deployment.Name
+
+		workloads = append(workloads, scaledWorkload{
+			kind:     "deployment",
+			meta:     &deployment.ObjectMeta,
+			replicas: deployment.Spec.Replicas,
+			template: &deployment.Spec.Template,
+			patchFn: func(patch []byte) error {
+				_, err := client.Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+				return err
+			},
+		})
+	}
+
+	statefulSets, err := c.statefulSetInformer.Lister().List(labels.Everything())
+	if err != nil {
+		log.Printf("failed to list statefulsets: %v", err)
+
+		return
+	}
+
+	for _, set := range statefulSets {
+		if _, ok := set.Annotations[scaleDownReplicasAnnotation]; !ok {
+			continue
+		}
+
+		client, name := c.clientset.AppsV1().StatefulSets(set.Namespace), set.Name
+
+		workloads = append(workloads, scaledWorkload{
+			kind:     "statefulset",
+			meta:     &set.ObjectMeta,
+			replicas: set.Spec.Replicas,
+			template: &set.Spec.Template,
+			patchFn: func(patch []byte) error {
+				_, err := client.Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+				return err
+			},
+		})
+	}
+
+	for _, workload := range workloads {
+		if err = c.reconcileWorkload(workload, index); err != nil {
+			log.Printf("failed to scale %s %s/%s: %v", workload.kind, workload.meta.Namespace, workload.meta.Name, err)
+		}
+	}
+}
+
+func (c *PodManager) reconcileWorkload(workload scaledWorkload, index int) error {
+	priority := c.templatePriority(&workload.template.Spec)
+
+	original, scaled := workload.meta.Annotations[originalReplicasAnnotation]
+
+	if scaled {
+		if priority <= int32(index) {
+			return nil
+		}
+
+		replicas, err := strconv.ParseInt(original, 10, 32)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("restoring %s %s/%s to %d replicas, priority is %d, index is %d", workload.kind, workload.meta.Namespace, workload.meta.Name, replicas, priority, index)
+
+		return workload.patch(int32(replicas), nil)
+	}
+
+	if priority >= int32(index) || c.confirmationRemaining(priority) > 0 {
+		return nil
+	}
+
+	target, err := strconv.ParseInt(workload.meta.Annotations[scaleDownReplicasAnnotation], 10, 32)
+	if err != nil {
+		return err
+	}
+
+	current := int32(1)
+	if workload.replicas != nil {
+		current = *workload.replicas
+	}
+
+	if current <= int32(target) {
+		return nil
+	}
+
+	log.Printf("scaling %s %s/%s down to %d replicas, priority is %d, index is %d", workload.kind, workload.meta.Namespace, workload.meta.Name, target, priority, index)
+
+	return workload.patch(int32(target), strconv.Itoa(int(current)))
+}
+
+// patch sets the replicas and the original replicas annotation; a nil
+// original removes the annotation.
+func (w scaledWorkload) patch(replicas int32, original interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				originalReplicasAnnotation: original,
+			},
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.patchFn(patch)
+}
@@ -0,0 +1,304 @@
+// Package controllers runs the node and pod managers.
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/siderolabs/kube-scheduler/pkg/controllers/node"
+	"github.com/siderolabs/kube-scheduler/pkg/controllers/pod"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+)
+
+// Default Lease the controllers are run under.
+const (
+	DefaultLeaseNamespace = "kube-system"
+	DefaultLeaseName      = "kube-scheduler-siderolabs-controllers"
+)
+
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// Options configures a Runtime.
+type Options struct {
+	// LeaderElect runs the controllers only while holding the Lease, so that
+	// a single replica evicts pods and powers nodes at a time.
+	LeaderElect bool
+	// LeaseNamespace and LeaseName locate the Lease.
+	LeaseNamespace string
+	LeaseName      string
+	// Identity identifies this process in the Lease.
+	Identity string
+}
+
+// DefaultOptions returns leader election on the default Lease, identified by
+// the hostname.
+func DefaultOptions() Options {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return Options{
+		LeaderElect:    true,
+		LeaseNamespace: DefaultLeaseNamespace,
+		LeaseName:      DefaultLeaseName,
+		Identity:       hostname + "_" + string(uuid.NewUUID()),
+	}
+}
+
+// Runtime runs the node and pod managers.
+type Runtime struct {
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
+	indexCache    *energy.Cache
+	args          *dynamicconfig.Source
+	options       Options
+
+	nodeManager *node.NodeManager
+	podManager  *pod.PodManager
+
+	mu      sync.RWMutex
+	running bool
+}
+
+// NewRuntime creates a Runtime whose controllers watch through the informer
+// factory, which must not have been started yet. Starting it is left to the
+// caller, such as the scheduler for its own factory. While the controllers
+// run, the Runtime also publishes the index for schedulers to requeue
+// deferred pods on.
+func NewRuntime(clientset kubernetes.Interface, dynamicClient dynamic.Interface, informerFactory informers.SharedInformerFactory, indexCache *energy.Cache, source *dynamicconfig.Source, options Options) (*Runtime, error) {
+	nodeManager, err := node.NewNodeManager(informerFactory, clientset, indexCache, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create node manager: %w", err)
+	}
+
+	podManager, err := pod.NewPodManager(informerFactory, clientset, indexCache, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pod manager: %w", err)
+	}
+
+	r := &Runtime{
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		indexCache:    indexCache,
+		args:          source,
+		options:       options,
+		nodeManager:   nodeManager,
+		podManager:    podManager,
+	}
+
+	indexCache.OnChange(func(index int) {
+		if r.Running() {
+			r.publishIndex(index)
+		}
+	})
+
+	return r, nil
+}
+
+var (
+	sharedOnce       sync.Once
+	shared           *Runtime
+	sharedIndexCache *energy.Cache
+	sharedErr        error
+)
+
+// Shared returns the Runtime of the process, started with the default
+// options on first use and run until ctx is done, releasing its Lease. Later
+// calls return the same Runtime, so that a single set of controllers acts on
+// the cluster; they fail if indexCache differs from the first one, as the
+// controllers follow a single provider. It reports whether the Runtime was
+// created by this call.
+func Shared(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, informerFactory informers.SharedInformerFactory, indexCache *energy.Cache, source *dynamicconfig.Source) (*Runtime, bool, error) {
+	created := false
+
+	sharedOnce.Do(func() {
+		created = true
+		sharedIndexCache = indexCache
+
+		shared, sharedErr = NewRuntime(clientset, dynamicClient, informerFactory, indexCache, source, DefaultOptions())
+		if sharedErr != nil {
+			return
+		}
+
+		go func() {
+			if err := shared.Run(ctx); err != nil {
+				log.Printf("controllers stopped: %v", err)
+			}
+		}()
+	})
+
+	if sharedErr != nil {
+		return nil, false, sharedErr
+	}
+
+	if indexCache != sharedIndexCache {
+		return nil, false, fmt.Errorf("profiles running the controllers must use the same provider settings, set disableControllers on the others")
+	}
+
+	return shared, created, nil
+}
+
+// AddProfile makes the pending pods of the scheduler profile power nodes on.
+func (r *Runtime) AddProfile(name string) {
+	r.nodeManager.AddProfile(name)
+}
+
+// PodManager returns the running PodManager, or nil while the controllers are
+// not running.
+func (r *Runtime) PodManager() *pod.PodManager {
+	if !r.Running() {
+		return nil
+	}
+
+	return r.podManager
+}
+
+// Running reports whether the controllers are running, that is whether this
+// replica holds the Lease if leader election is enabled.
+func (r *Runtime) Running() bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.running
+}
+
+// Run runs the controllers until ctx is done, while holding the Lease if
+// leader election is enabled.
+func (r *Runtime) Run(ctx context.Context) error {
+	if !r.options.LeaderElect {
+		if err := r.runControllers(ctx); err != nil {
+			return err
+		}
+
+		<-ctx.Done()
+
+		return nil
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: r.options.LeaseNamespace,
+			Name:      r.options.LeaseName,
+		},
+		Client: r.clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: r.options.Identity,
+		},
+	}
+
+	electionConfig := leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            r.options.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStoppedLeading: func() {
+				if r.Running() {
+					log.Printf("lost lease %s/%s, stopping controllers", r.options.LeaseNamespace, r.options.LeaseName)
+				}
+
+				r.setRunning(false)
+			},
+		},
+	}
+
+	// Keep competing for the Lease after losing it; each term runs new
+	// controllers, stopped with the term's context. A term whose controllers
+	// fail to start is ended, releasing the Lease to another replica.
+	for ctx.Err() == nil {
+		termCtx, endTerm := context.WithCancel(ctx)
+		failed := false
+
+		electionConfig.Callbacks.OnStartedLeading = func(ctx context.Context) {
+			log.Printf("acquired lease %s/%s, starting controllers", r.options.LeaseNamespace, r.options.LeaseName)
+
+			if err := r.runControllers(ctx); err != nil {
+				log.Printf("failed to start controllers, releasing lease: %v", err)
+
+				failed = true
+
+				endTerm()
+			}
+		}
+
+		elector, err := leaderelection.NewLeaderElector(electionConfig)
+		if err != nil {
+			endTerm()
+
+			return err
+		}
+
+		elector.Run(termCtx)
+		endTerm()
+
+		if failed {
+			select {
+			case <-ctx.Done():
+			case <-time.After(leaseDuration):
+			}
+		}
+	}
+
+	return nil
+}
+
+// runControllers starts the controllers until ctx is done.
+func (r *Runtime) runControllers(ctx context.Context) error {
+	if err := r.nodeManager.Run(ctx.Done()); err != nil {
+		return fmt.Errorf("failed to run node manager: %w", err)
+	}
+
+	if err := r.podManager.Run(ctx.Done()); err != nil {
+		return fmt.Errorf("failed to run pod manager: %w", err)
+	}
+
+	r.setRunning(true)
+
+	// The previous holder of the Lease may have published another index.
+	if index, err := r.indexCache.Index(); err == nil {
+		r.publishIndex(index)
+	}
+
+	return nil
+}
+
+// publishIndex publishes the index of the provider region the controllers
+// follow.
+func (r *Runtime) publishIndex(index int) {
+	args := r.args.Args()
+
+	if err := energy.PublishIndex(r.dynamicClient, args.Provider, args.WattTimeBA, index); err != nil {
+		log.Printf("failed to publish index: %v", err)
+	}
+}
+
+func (r *Runtime) setRunning(running bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.running = running
+}
@@ -0,0 +1,204 @@
+// Package dynamicconfig follows the tunable settings of EmissionsArgs in a
+// ConfigMap, so that they change without a restart.
+package dynamicconfig
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/apis/config/scheme"
+	"github.com/siderolabs/kube-scheduler/apis/config/validation"
+)
+
+// configKey is the key of the ConfigMap holding the EmissionsArgs.
+const configKey = "config"
+
+// Source holds the EmissionsArgs in effect: those given on startup, with the
+// tunable settings replaced by those of the dynamic ConfigMap, if any. The
+// components sharing a Source read the args from it, so that validated args
+// replace the previous ones for all of them at once.
+type Source struct {
+	static  *config.EmissionsArgs
+	current atomic.Pointer[config.EmissionsArgs]
+
+	mu        sync.Mutex
+	listeners []func(args *config.EmissionsArgs)
+}
+
+// NewSource creates a Source starting from the startup args.
+func NewSource(args *config.EmissionsArgs) *Source {
+	s := &Source{static: args}
+	s.current.Store(args)
+
+	return s
+}
+
+// Args returns the args in effect. They must not be modified.
+func (s *Source) Args() *config.EmissionsArgs {
+	return s.current.Load()
+}
+
+// OnChange registers fn to be called with the new args whenever they change,
+// once they are in effect, for changes that are not read from Args.
+func (s *Source) OnChange(fn func(args *config.EmissionsArgs)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, fn)
+}
+
+// Run follows the ConfigMap referenced by the startup args until stopCh is
+// closed. It returns at once if there is none.
+func (s *Source) Run(clientset kubernetes.Interface, stopCh <-chan struct{}) {
+	ref := s.static.DynamicConfigMapRef
+	if ref == nil {
+		return
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(ref.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", ref.Name).String()
+		}),
+	)
+
+	_, err := factory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: s.configMapAdd,
+		UpdateFunc: func(_, obj interface{}) {
+			s.configMapAdd(obj)
+		},
+		DeleteFunc: s.configMapDelete,
+	})
+	if err != nil {
+		log.Printf("failed to watch ConfigMap %s/%s: %v", ref.Namespace, ref.Name, err)
+
+		return
+	}
+
+	factory.Start(stopCh)
+	<-stopCh
+	factory.Shutdown()
+}
+
+func (s *Source) configMapAdd(obj interface{}) {
+	configMap, ok := obj.(*v1.ConfigMap)
+	if !ok {
+		return
+	}
+
+	args, err := s.merge(configMap)
+	if err != nil {
+		log.Printf("ignoring ConfigMap %s/%s: %v", configMap.Namespace, configMap.Name, err)
+
+		return
+	}
+
+	log.Printf("applying EmissionsArgs from ConfigMap %s/%s", configMap.Namespace, configMap.Name)
+
+	s.set(args)
+}
+
+func (s *Source) configMapDelete(obj interface{}) {
+	log.Printf("dynamic ConfigMap deleted, restoring the startup EmissionsArgs")
+
+	s.set(s.static)
+}
+
+// merge returns the startup args with the tunable settings of the ConfigMap,
+// once validated. Settings missing from the ConfigMap keep their startup
+// values, so it is decoded onto the startup args, in its own version, rather
+// than defaulted.
+func (s *Source) merge(configMap *v1.ConfigMap) (*config.EmissionsArgs, error) {
+	data, ok := configMap.Data[configKey]
+	if !ok {
+		return nil, fmt.Errorf("no %q key", configKey)
+	}
+
+	// Strictly decoded first to find the version and reject unknown fields.
+	_, gvk, err := scheme.Codecs.UniversalDeserializer().Decode([]byte(data), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %q: %w", configKey, err)
+	}
+
+	if gvk.Kind != "EmissionsArgs" {
+		return nil, fmt.Errorf("want %q to be of kind EmissionsArgs, got %s", configKey, gvk.Kind)
+	}
+
+	versioned, err := scheme.Scheme.New(*gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = scheme.Scheme.Convert(s.static, versioned, nil); err != nil {
+		return nil, fmt.Errorf("failed to convert the startup args to %s: %w", gvk.GroupVersion(), err)
+	}
+
+	if err = yaml.Unmarshal([]byte(data), versioned); err != nil {
+		return nil, fmt.Errorf("failed to decode %q: %w", configKey, err)
+	}
+
+	dynamic := &config.EmissionsArgs{}
+	if err = scheme.Scheme.Convert(versioned, dynamic, nil); err != nil {
+		return nil, fmt.Errorf("failed to convert %q: %w", configKey, err)
+	}
+
+	args := s.static.DeepCopy()
+	copyTunables(args, dynamic)
+
+	if err = validation.ValidateEmissionsArgs(field.NewPath("data", configKey), args); err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// set puts the args in effect in a single swap and then calls the listeners.
+func (s *Source) set(args *config.EmissionsArgs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current.Store(args)
+
+	for _, fn := range s.listeners {
+		fn(args)
+	}
+}
+
+// copyTunables copies the settings that may change at runtime. The others,
+// such as the provider, the deferral mode and the informers the controllers
+// watch, are only read on startup.
+func copyTunables(dst, src *config.EmissionsArgs) {
+	dst.IndexRefreshInterval = src.IndexRefreshInterval
+	dst.FailurePolicy = src.FailurePolicy
+	dst.MaxWaitPolicy = src.MaxWaitPolicy
+
+	dst.NodeIdleDuration = src.NodeIdleDuration
+	dst.IdleIgnoredNamespaces = src.IdleIgnoredNamespaces
+	dst.SchedulerNames = src.SchedulerNames
+	dst.WakeOnLANBroadcastAddress = src.WakeOnLANBroadcastAddress
+	dst.MinNodeUptime = src.MinNodeUptime
+	dst.MinNodeDowntime = src.MinNodeDowntime
+	dst.IndexHysteresis = src.IndexHysteresis
+	dst.MaxPowerCyclesPerDay = src.MaxPowerCyclesPerDay
+	dst.NodePoolLabel = src.NodePoolLabel
+	dst.NodePools = src.NodePools
+
+	dst.MaxEvictionsPerMinute = src.MaxEvictionsPerMinute
+	dst.MaxNamespaceEvictionsPerMinute = src.MaxNamespaceEvictionsPerMinute
+	dst.MaxEvictedReplicasPercent = src.MaxEvictedReplicasPercent
+	dst.EvictionConfirmationPeriod = src.EvictionConfirmationPeriod
+	dst.EvictionConfirmationSamples = src.EvictionConfirmationSamples
+	dst.EvictionGracePeriodSeconds = src.EvictionGracePeriodSeconds
+}
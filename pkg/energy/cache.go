@@ -0,0 +1,185 @@
+package energy
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// maxSampleAgeIntervals is how many refresh intervals the latest sample is
+// used for, in case refreshes stall.
+const maxSampleAgeIntervals = 3
+
+// maxSamples bounds the index history kept by the cache.
+const maxSamples = 288
+
+// Provider reports the current emissions index of a region, as a percentile
+// where higher means dirtier.
+type Provider interface {
+	Index() (int, error)
+}
+
+// Sample is an index reading.
+type Sample struct {
+	Index int
+	Time  time.Time
+}
+
+// Cache polls a provider at a fixed interval and keeps the recent index
+// history, so that callers neither hit the provider on every decision nor
+// act on a single reading.
+type Cache struct {
+	provider Provider
+	// intervalChanged wakes Run up when the interval changes.
+	intervalChanged chan struct{}
+
+	mu        sync.RWMutex
+	interval  time.Duration
+	samples   []Sample
+	err       error
+	listeners []func(index int)
+}
+
+// NewCache creates a Cache that refreshes from the provider every interval.
+func NewCache(provider Provider, interval time.Duration) *Cache {
+	return &Cache{provider: provider, interval: interval, intervalChanged: make(chan struct{}, 1)}
+}
+
+// Run refreshes the cache until stopCh is closed.
+func (c *Cache) Run(stopCh <-chan struct{}) {
+	go func() {
+		c.refresh()
+
+		for {
+			timer := time.NewTimer(c.Interval())
+
+			select {
+			case <-stopCh:
+				timer.Stop()
+
+				return
+			case <-c.intervalChanged:
+				timer.Stop()
+			case <-timer.C:
+				c.refresh()
+			}
+		}
+	}()
+}
+
+// Interval returns how often the cache is refreshed.
+func (c *Cache) Interval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.interval
+}
+
+// SetInterval changes how often the cache is refreshed, starting a new
+// interval from now.
+func (c *Cache) SetInterval(interval time.Duration) {
+	c.mu.Lock()
+	changed := c.interval != interval
+	c.interval = interval
+	c.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	select {
+	case c.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Cache) refresh() {
+	index, err := c.provider.Index()
+
+	c.mu.Lock()
+
+	// Recovering from a failure is a change too, as the index was
+	// unavailable meanwhile.
+	recovered := c.err != nil
+
+	c.err = err
+	if err != nil {
+		c.mu.Unlock()
+
+		log.Printf("failed to refresh index: %v", err)
+
+		return
+	}
+
+	changed := recovered || len(c.samples) == 0 || c.samples[len(c.samples)-1].Index != index
+
+	c.samples = append(c.samples, Sample{Index: index, Time: time.Now()})
+	if len(c.samples) > maxSamples {
+		c.samples = c.samples[len(c.samples)-maxSamples:]
+	}
+
+	listeners := c.listeners
+
+	c.mu.Unlock()
+
+	if changed {
+		for _, fn := range listeners {
+			fn(index)
+		}
+	}
+}
+
+// OnChange registers fn to be called with the new index whenever it changes.
+func (c *Cache) OnChange(fn func(index int)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.listeners = append(c.listeners, fn)
+}
+
+// Index returns the latest index. It fails when the last refresh failed or
+// the latest sample is stale, so that the failure policy applies.
+func (c *Cache) Index() (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.err != nil {
+		return -1, c.err
+	}
+
+	if len(c.samples) == 0 {
+		return -1, fmt.Errorf("index not available yet")
+	}
+
+	latest := c.samples[len(c.samples)-1]
+
+	if age := time.Since(latest.Time); age > maxSampleAgeIntervals*c.interval {
+		return -1, fmt.Errorf("index is stale, last refreshed %s ago", age.Round(time.Second))
+	}
+
+	return latest.Index, nil
+}
+
+// AboveSince returns the number of consecutive latest samples with an index
+// above the threshold, and the time of the first of them.
+func (c *Cache) AboveSince(threshold int) (int, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var (
+		count int
+		since time.Time
+	)
+
+	for i := len(c.samples) - 1; i >= 0; i-- {
+		if c.samples[i].Index <= threshold {
+			break
+		}
+
+		count++
+		since = c.samples[i].Time
+	}
+
+	return count, since
+}
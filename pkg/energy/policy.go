@@ -0,0 +1,7 @@
+package energy
+
+// Allows reports whether pods of the given priority may be scheduled at the
+// index.
+func Allows(priority int32, index int) bool {
+	return priority > int32(index)
+}
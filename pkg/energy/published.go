@@ -0,0 +1,92 @@
+package energy
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// IndexResource is the resource the index of each provider region is
+// published to whenever it changes, one cluster-scoped object per region, so
+// that schedulers get a cluster event to requeue deferred pods on. Schedulers
+// watch every object of the resource, so it is a resource of its own rather
+// than, say, a ConfigMap, which would cache all ConfigMaps of the cluster.
+var IndexResource = schema.GroupVersionResource{
+	Group:    "carbon.siderolabs.com",
+	Version:  "v1alpha1",
+	Resource: "emissionsindexes",
+}
+
+// IndexEventResource is IndexResource in the <resource>.<version>.<group>
+// form the scheduler builds a dynamic informer for.
+const IndexEventResource = "emissionsindexes.v1alpha1.carbon.siderolabs.com"
+
+const indexKind = "EmissionsIndex"
+
+// PublishIndex writes the index of the provider region to its object.
+func PublishIndex(client dynamic.Interface, provider, region string, index int) error {
+	resource := client.Resource(IndexResource)
+	name := indexObjectName(provider, region)
+
+	obj, err := resource.Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj = &unstructured.Unstructured{}
+		obj.SetAPIVersion(IndexResource.GroupVersion().String())
+		obj.SetKind(indexKind)
+		obj.SetName(name)
+		setPublishedIndex(obj, provider, region, index)
+
+		_, err = resource.Create(context.TODO(), obj, metav1.CreateOptions{})
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	setPublishedIndex(obj, provider, region, index)
+
+	_, err = resource.Update(context.TODO(), obj, metav1.UpdateOptions{})
+
+	return err
+}
+
+// PublishedIndex extracts the provider region and its index from a published
+// object, as delivered by a dynamic informer.
+func PublishedIndex(obj interface{}) (provider, region string, index int, ok bool) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || u.GetKind() != indexKind {
+		return "", "", 0, false
+	}
+
+	provider, _, _ = unstructured.NestedString(u.Object, "provider")
+	region, _, _ = unstructured.NestedString(u.Object, "region")
+
+	value, found, err := unstructured.NestedInt64(u.Object, "index")
+	if err != nil || !found {
+		return "", "", 0, false
+	}
+
+	return provider, region, int(value), true
+}
+
+func setPublishedIndex(obj *unstructured.Unstructured, provider, region string, index int) {
+	obj.Object["provider"] = provider
+	obj.Object["region"] = region
+	obj.Object["index"] = int64(index)
+}
+
+// indexObjectName names the object of the provider region. Regions are not
+// valid object names, so they are hashed.
+func indexObjectName(provider, region string) string {
+	sum := sha256.Sum256([]byte(provider + "/" + region))
+
+	return fmt.Sprintf("emissions-index-%x", sum[:8])
+}
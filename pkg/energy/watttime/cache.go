@@ -0,0 +1,55 @@
+package watttime
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+)
+
+const loginInterval = 15 * time.Minute
+
+// NewCache creates a WattTime client from args, keeps it logged in with
+// up-to-date credentials until stopCh is closed, and returns a cache of its
+// index. The cache is not run. The clientset is only used to read credentials
+// from a Secret, and may be nil otherwise.
+func NewCache(args *config.EmissionsArgs, clientset kubernetes.Interface, stopCh <-chan struct{}) (*energy.Cache, error) {
+	client := NewClient(args.WattTimeUsername, args.WattTimePassword, args.WattTimeBA)
+
+	switch {
+	case args.CredentialsSecretRef != nil:
+		if clientset == nil {
+			return nil, fmt.Errorf("no clientset to read secret %s/%s with", args.CredentialsSecretRef.Namespace, args.CredentialsSecretRef.Name)
+		}
+
+		creds, err := readCredentialsSecret(clientset, args.CredentialsSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		client.SetCredentials(creds.Username, creds.Password)
+
+		go client.WatchCredentialsSecret(clientset, args.CredentialsSecretRef, stopCh)
+	case args.CredentialsFile != "":
+		creds, err := readCredentialsFile(args.CredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		client.SetCredentials(creds.Username, creds.Password)
+
+		go client.WatchCredentialsFile(args.CredentialsFile, stopCh)
+	}
+
+	if err := client.Login(); err != nil {
+		log.Printf("failed to login to WattTime: %v\n", err)
+	}
+
+	go client.KeepLoggedIn(loginInterval, stopCh)
+
+	return energy.NewCache(client, args.IndexRefreshInterval.Duration), nil
+}
@@ -0,0 +1,146 @@
+package watttime
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Keys of the credentials in a Secret or file.
+const (
+	usernameKey = "username"
+	passwordKey = "password"
+)
+
+// secretRefreshInterval is how often credentials are read again from their
+// Secret.
+const secretRefreshInterval = time.Minute
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// readCredentialsSecret reads the credentials from the Secret.
+func readCredentialsSecret(clientset kubernetes.Interface, ref *v1.SecretReference) (credentials, error) {
+	secret, err := clientset.CoreV1().Secrets(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return credentials{}, fmt.Errorf("failed to get secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+
+	creds := credentials{
+		Username: string(secret.Data[usernameKey]),
+		Password: string(secret.Data[passwordKey]),
+	}
+
+	if creds.Username == "" || creds.Password == "" {
+		return credentials{}, fmt.Errorf("secret %s/%s is missing %q or %q", ref.Namespace, ref.Name, usernameKey, passwordKey)
+	}
+
+	return creds, nil
+}
+
+// readCredentialsFile reads the credentials from a YAML file.
+func readCredentialsFile(path string) (credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return credentials{}, err
+	}
+
+	var creds credentials
+
+	if err = yaml.UnmarshalStrict(data, &creds); err != nil {
+		return credentials{}, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	if creds.Username == "" || creds.Password == "" {
+		return credentials{}, fmt.Errorf("%s is missing %q or %q", path, usernameKey, passwordKey)
+	}
+
+	return creds, nil
+}
+
+// updateCredentials sets the credentials and logs in again if they changed.
+func (c *Client) updateCredentials(creds credentials, source string) {
+	if !c.SetCredentials(creds.Username, creds.Password) {
+		return
+	}
+
+	log.Printf("WattTime credentials changed in %s, logging in again", source)
+
+	if err := c.Login(); err != nil {
+		log.Printf("failed to login to WattTime: %v\n", err)
+	}
+}
+
+// WatchCredentialsSecret reads the credentials from the Secret every minute
+// until stopCh is closed, logging in again when they change.
+func (c *Client) WatchCredentialsSecret(clientset kubernetes.Interface, ref *v1.SecretReference, stopCh <-chan struct{}) {
+	source := fmt.Sprintf("secret %s/%s", ref.Namespace, ref.Name)
+
+	wait.Until(func() {
+		creds, err := readCredentialsSecret(clientset, ref)
+		if err != nil {
+			log.Printf("failed to read WattTime credentials: %v", err)
+
+			return
+		}
+
+		c.updateCredentials(creds, source)
+	}, secretRefreshInterval, stopCh)
+}
+
+// WatchCredentialsFile reads the credentials from the file whenever it
+// changes until stopCh is closed, logging in again when they change.
+//
+// The directory of the file is watched, as Secrets and ConfigMaps are mounted
+// as symlinks that are replaced on update.
+func (c *Client) WatchCredentialsFile(path string, stopCh <-chan struct{}) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("failed to watch WattTime credentials: %v", err)
+
+		return
+	}
+
+	defer watcher.Close()
+
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		log.Printf("failed to watch WattTime credentials: %v", err)
+
+		return
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case err := <-watcher.Errors:
+			log.Printf("failed to watch WattTime credentials: %v", err)
+		case event := <-watcher.Events:
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			creds, err := readCredentialsFile(path)
+			if err != nil {
+				// The file may be in the middle of being replaced.
+				log.Printf("failed to read WattTime credentials: %v", err)
+
+				continue
+			}
+
+			c.updateCredentials(creds, path)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -15,6 +16,10 @@ type Client struct {
 	Password string
 	BA       string
 	token    string
+
+	// mu guards the credentials and the token, which change while the
+	// client is in use.
+	mu sync.RWMutex
 }
 
 type LoginResponse struct {
@@ -32,13 +37,30 @@ func NewClient(username, password, ba string) *Client {
 	return &Client{Username: username, Password: password, BA: ba}
 }
 
+// SetCredentials replaces the credentials used to log in, and reports
+// whether they changed.
+func (c *Client) SetCredentials(username, password string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Username == username && c.Password == password {
+		return false
+	}
+
+	c.Username, c.Password = username, password
+
+	return true
+}
+
 func (c *Client) Login() error {
 	req, err := http.NewRequest(http.MethodGet, "https://api2.watttime.org/v2/login", nil)
 	if err != nil {
 		return err
 	}
 
+	c.mu.RLock()
 	req.SetBasicAuth(c.Username, c.Password)
+	c.mu.RUnlock()
 
 	client := http.Client{
 		Timeout: 30 * time.Second,
@@ -65,11 +87,32 @@ func (c *Client) Login() error {
 		return fmt.Errorf("failed to unmarshal response: %v", err)
 	}
 
+	c.mu.Lock()
 	c.token = login.Token
+	c.mu.Unlock()
 
 	return nil
 }
 
+// KeepLoggedIn logs in again every interval, before the token expires, until
+// stopCh is closed.
+func (c *Client) KeepLoggedIn(interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.Login(); err != nil {
+			log.Printf("failed to login to WattTime: %v\n", err)
+		}
+	}
+}
+
 func (c *Client) Index() (int, error) {
 	req, err := http.NewRequest(http.MethodGet, "https://api2.watttime.org/index", nil)
 	if err != nil {
@@ -81,7 +124,9 @@ func (c *Client) Index() (int, error) {
 	q.Add("style", "percent")
 	req.URL.RawQuery = q.Encode()
 
+	c.mu.RLock()
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	c.mu.RUnlock()
 
 	client := http.Client{
 		Timeout: 30 * time.Second,
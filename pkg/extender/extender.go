@@ -0,0 +1,192 @@
+// Package extender serves the emissions policy as a scheduler extender, for
+// schedulers that run without the Emissions plugin.
+package extender
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+	"github.com/siderolabs/kube-scheduler/pkg/power"
+)
+
+// Paths the extender serves, to be set as filterVerb and prioritizeVerb
+// relative to urlPrefix.
+const (
+	FilterPath     = "/filter"
+	PrioritizePath = "/prioritize"
+)
+
+// Extender filters and prioritizes nodes for pods based on the emissions
+// index, as the Emissions plugin does.
+type Extender struct {
+	indexCache *energy.Cache
+	args       *dynamicconfig.Source
+}
+
+// New creates an Extender deciding on the index in the cache.
+func New(indexCache *energy.Cache, source *dynamicconfig.Source) *Extender {
+	return &Extender{indexCache: indexCache, args: source}
+}
+
+// Filter keeps all nodes for pods whose priority is above the index, and none
+// otherwise. Pods without a priority never fit, while pods deferred because of
+// the index are retried, as are all pods while the index is unavailable unless
+// the failure policy allows them.
+func (e *Extender) Filter(args *extenderv1.ExtenderArgs) *extenderv1.ExtenderFilterResult {
+	result := &extenderv1.ExtenderFilterResult{
+		Nodes:     args.Nodes,
+		NodeNames: args.NodeNames,
+	}
+
+	pod := args.Pod
+	if pod == nil {
+		result.Error = "no pod in extender arguments"
+
+		return result
+	}
+
+	if pod.Spec.Priority == nil {
+		return reject(args, result, "no priority set on pod", true)
+	}
+
+	index, err := e.indexCache.Index()
+	if err != nil {
+		if e.args.Args().FailurePolicy == config.FailurePolicyAllow {
+			return result
+		}
+
+		return reject(args, result, fmt.Sprintf("failed to get index: %v", err), false)
+	}
+
+	if energy.Allows(*pod.Spec.Priority, index) {
+		return result
+	}
+
+	return reject(args, result, fmt.Sprintf("pod priority (%d) lower than index (%d)", *pod.Spec.Priority, index), false)
+}
+
+// reject fails all nodes of args with the reason.
+func reject(args *extenderv1.ExtenderArgs, result *extenderv1.ExtenderFilterResult, reason string, unresolvable bool) *extenderv1.ExtenderFilterResult {
+	failed := extenderv1.FailedNodesMap{}
+
+	for _, name := range nodeNames(args) {
+		failed[name] = reason
+	}
+
+	if unresolvable {
+		result.FailedAndUnresolvableNodes = failed
+	} else {
+		result.FailedNodes = failed
+	}
+
+	if result.Nodes != nil {
+		result.Nodes = &v1.NodeList{}
+	}
+
+	if result.NodeNames != nil {
+		result.NodeNames = &[]string{}
+	}
+
+	return result
+}
+
+// Prioritize scores nodes that are not power managed highest, as the plugin's
+// Score does, so that power managed nodes are left idle and can be powered
+// off.
+func (e *Extender) Prioritize(args *extenderv1.ExtenderArgs) *extenderv1.HostPriorityList {
+	priorities := extenderv1.HostPriorityList{}
+
+	if args.Nodes != nil {
+		for i := range args.Nodes.Items {
+			node := &args.Nodes.Items[i]
+
+			priorities = append(priorities, extenderv1.HostPriority{Host: node.Name, Score: score(node)})
+		}
+
+		return &priorities
+	}
+
+	// Without node objects, as with nodeCacheCapable, power management is
+	// unknown and all nodes score the same.
+	for _, name := range nodeNames(args) {
+		priorities = append(priorities, extenderv1.HostPriority{Host: name})
+	}
+
+	return &priorities
+}
+
+func score(n *v1.Node) int64 {
+	if power.IsManaged(n) {
+		return extenderv1.MinExtenderPriority
+	}
+
+	return extenderv1.MaxExtenderPriority
+}
+
+func nodeNames(args *extenderv1.ExtenderArgs) []string {
+	if args.NodeNames != nil {
+		return *args.NodeNames
+	}
+
+	if args.Nodes == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(args.Nodes.Items))
+	for _, node := range args.Nodes.Items {
+		names = append(names, node.Name)
+	}
+
+	return names
+}
+
+// Handler returns the HTTP handler serving the extender.
+func (e *Extender) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(FilterPath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, func(args *extenderv1.ExtenderArgs) interface{} {
+			return e.Filter(args)
+		})
+	})
+
+	mux.HandleFunc(PrioritizePath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, func(args *extenderv1.ExtenderArgs) interface{} {
+			return e.Prioritize(args)
+		})
+	})
+
+	return mux
+}
+
+// serve decodes the ExtenderArgs of the request and encodes the result of fn
+// as the response.
+func serve(w http.ResponseWriter, r *http.Request, fn func(args *extenderv1.ExtenderArgs) interface{}) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	var args extenderv1.ExtenderArgs
+
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode extender arguments: %v", err), http.StatusBadRequest)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(fn(&args)); err != nil {
+		log.Printf("failed to encode extender result: %v", err)
+	}
+}
@@ -3,29 +3,37 @@ package emissions
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/informers"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
 
 	"github.com/siderolabs/kube-scheduler/apis/config"
-	"github.com/siderolabs/kube-scheduler/pkg/controllers/node"
-	"github.com/siderolabs/kube-scheduler/pkg/controllers/pod"
-	"github.com/siderolabs/kube-scheduler/pkg/energy/watttime"
+	"github.com/siderolabs/kube-scheduler/apis/config/validation"
+	"github.com/siderolabs/kube-scheduler/pkg/controllers"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
 )
 
 // Emissions is a prefilter plugin that schedules pods based
 // on the current emssisions score for a region.
 // Implements framework.ScorePlugin
 type Emissions struct {
-	handle framework.Handle
-	args   *config.EmissionsArgs
+	handle      framework.Handle
+	args        *dynamicconfig.Source
+	indexCache  *energy.Cache
+	podLister   corelisters.PodLister
+	pdbLister   policylisters.PodDisruptionBudgetLister
+	controllers *controllers.Runtime
+
+	defaultPreemption *defaultpreemption.DefaultPreemption
 }
 
 // Name is the name of the plugin used in the Registry and configurations.
@@ -33,8 +41,10 @@ const Name = "Emissions"
 
 var _ = framework.PreFilterPlugin(&Emissions{})
 
-// New initializes a new plugin and returns it.
-func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+// New initializes a new plugin and returns it. Its background work, and that
+// of the caches and controllers it shares with other profiles, stops once ctx
+// is done.
+func New(ctx context.Context, obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
 	args, ok := obj.(*config.EmissionsArgs)
 	if !ok {
 		return nil, fmt.Errorf("[Emissions] want args to be of type EmissionsArgs, got %T", obj)
@@ -42,46 +52,82 @@ func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
 
 	klog.Infof("[Emissions] args received. %v", args)
 
-	ctx := context.TODO()
+	if err := validation.ValidateEmissionsArgs(nil, args); err != nil {
+		return nil, fmt.Errorf("[Emissions] invalid args: %w", err)
+	}
+
+	// The scheduler's clientset and informers are reused, so that the plugin
+	// runs wherever the scheduler does and shares its watches.
+	clientset := h.ClientSet()
+
+	// Tunable settings follow the dynamic ConfigMap, if any, all at once.
+	source := dynamicconfig.NewSource(args)
 
-	config, err := rest.InClusterConfig()
+	// Profiles with the same provider settings share a cache, while their
+	// thresholds and modes only apply at their own extension points.
+	indexCache, ownsCache, err := sharedCache(args, clientset, ctx.Done())
 	if err != nil {
 		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, err
+	// The controllers are shared by all profiles, and run by a single
+	// replica at a time.
+	var sharedControllers *controllers.Runtime
+
+	if !args.DisableControllers {
+		// The replica running the controllers publishes the index, which
+		// requeues deferred pods in every replica.
+		indexClient, err := dynamic.NewForConfig(h.KubeConfig())
+		if err != nil {
+			return nil, fmt.Errorf("[Emissions] failed to create dynamic client: %w", err)
+		}
+
+		sharedControllers, _, err = controllers.Shared(ctx, clientset, indexClient, h.SharedInformerFactory(), indexCache, source)
+		if err != nil {
+			return nil, err
+		}
+
+		// Nodes are powered on for the pending pods of every profile
+		// running the plugin.
+		if profile, ok := h.(framework.Framework); ok {
+			sharedControllers.AddProfile(profile.ProfileName())
+		}
 	}
 
-	wattTimeClient := watttime.NewClient(args.WattTimeUsername, args.WattTimePassword, args.WattTimeBA)
-	err = wattTimeClient.Login()
+	defaultPreemption, err := newDefaultPreemption(h, args)
 	if err != nil {
-		log.Printf("failed to login to WattTime: %v\n", err)
+		return nil, err
 	}
 
-	go wattTimeLoginLoop(wattTimeClient)
+	e := &Emissions{
+		handle:      h,
+		args:        source,
+		indexCache:  indexCache,
+		podLister:   h.SharedInformerFactory().Core().V1().Pods().Lister(),
+		pdbLister:   h.SharedInformerFactory().Policy().V1().PodDisruptionBudgets().Lister(),
+		controllers: sharedControllers,
 
-	nodeFactory := informers.NewSharedInformerFactory(clientset, 5*time.Minute)
-	nodeManager, err := node.NewNodeManager(nodeFactory, clientset, wattTimeClient)
-	if err != nil {
-		klog.Fatal(err)
+		defaultPreemption: defaultPreemption,
 	}
 
-	nodeManager.Run(ctx.Done())
-
-	podFactory := informers.NewSharedInformerFactory(clientset, 5*time.Minute)
-	podManager, err := pod.NewPodManager(podFactory, clientset, wattTimeClient)
-	if err != nil {
-		klog.Fatal(err)
+	// The shared cache and controllers follow the settings of the profile
+	// that created them.
+	if ownsCache {
+		source.OnChange(func(args *config.EmissionsArgs) {
+			indexCache.SetInterval(args.IndexRefreshInterval.Duration)
+		})
 	}
 
-	podManager.Run(ctx.Done())
+	go source.Run(clientset, ctx.Done())
 
-	return &Emissions{
-		handle: h,
-		args:   args,
-	}, nil
+	// Pods are held at Permit in Permit deferral mode and for groups.
+	indexCache.OnChange(func(int) {
+		e.reviewWaitingPods()
+	})
+
+	go wait.Until(e.reviewWaitingPods, waitingPodsReviewInterval, ctx.Done())
+
+	return e, nil
 }
 
 // Name returns name of the plugin. It is used in logs, etc.
@@ -95,38 +141,39 @@ func (e *Emissions) PreFilter(ctx context.Context, state *framework.CycleState,
 		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, "no priority set on pod")
 	}
 
-	wattTimeClient := watttime.NewClient(e.args.WattTimeUsername, e.args.WattTimePassword, e.args.WattTimeBA)
+	// Pods are held at Permit instead, unless they already waited there for
+	// as long as allowed.
+	args := e.args.Args()
 
-	err := wattTimeClient.Login()
-	if err != nil {
-		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("failed to log in to WattTime: %v", err))
+	if args.DeferralMode == config.DeferralModePermit &&
+		!(args.MaxWaitPolicy == config.MaxWaitPolicyReject && maxWaitExpired(pod, time.Now())) {
+		return nil, framework.NewStatus(framework.Success, "")
 	}
 
-	index, err := wattTimeClient.Index()
+	index, err := e.indexCache.Index()
 	if err != nil {
-		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("failed to get index from WattTime: %v", err))
+		if args.FailurePolicy == config.FailurePolicyAllow {
+			return nil, framework.NewStatus(framework.Success, "")
+		}
+
+		return nil, e.deferPod(state, fmt.Sprintf("failed to get index: %v", err))
 	}
 
-	if *pod.Spec.Priority > int32(index) {
+	if energy.Allows(*pod.Spec.Priority, index) {
 		return nil, framework.NewStatus(framework.Success, "")
 	}
 
-	return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("pod priority (%d) lower than index (%d)", *pod.Spec.Priority, index))
-}
-
-func (e *Emissions) PreFilterExtensions() framework.PreFilterExtensions {
-	return nil
+	return nil, e.deferPod(state, fmt.Sprintf("pod priority (%d) lower than index (%d)", *pod.Spec.Priority, index))
 }
 
-func wattTimeLoginLoop(wattTimeClient *watttime.Client) {
-	tick := time.Tick(15 * time.Minute)
+// deferPod rejects the pod for this cycle only; the index is transient, so
+// the pod is kept pending and requeued once the index drops.
+func (e *Emissions) deferPod(state *framework.CycleState, message string) *framework.Status {
+	state.Write(deferredStateKey, &deferredState{message: message})
 
-	for {
-		<-tick
+	return framework.NewStatus(framework.Unschedulable, message)
+}
 
-		err := wattTimeClient.Login()
-		if err != nil {
-			log.Printf("failed to login to WattTime: %v\n", err)
-		}
-	}
+func (e *Emissions) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
 }
@@ -0,0 +1,108 @@
+package emissions
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// groupAnnotation names the group a pod belongs to, within its namespace.
+// Pods of a group are admitted together, once all of them are placed and the
+// index is below all of their priorities, so that a brief dip in the index
+// does not start only part of a group.
+const groupAnnotation = "carbon.siderolabs.com/group"
+
+// groupSizeAnnotation is the number of pods in the group.
+const groupSizeAnnotation = "carbon.siderolabs.com/group-size"
+
+type podGroup struct {
+	namespace string
+	name      string
+	size      int
+}
+
+func podGroupOf(pod *v1.Pod) (podGroup, bool) {
+	name, ok := pod.Annotations[groupAnnotation]
+	if !ok || name == "" {
+		return podGroup{}, false
+	}
+
+	size, err := strconv.Atoi(pod.Annotations[groupSizeAnnotation])
+	if err != nil || size < 1 {
+		return podGroup{}, false
+	}
+
+	return podGroup{namespace: pod.Namespace, name: name, size: size}, true
+}
+
+func (g podGroup) has(pod *v1.Pod) bool {
+	return pod.Namespace == g.namespace && pod.Annotations[groupAnnotation] == g.name
+}
+
+// admitGroup admits the pod together with the other members of its group
+// waiting at Permit, once the whole group is placed and none of its members
+// is deferred. Otherwise the pod keeps waiting.
+func (e *Emissions) admitGroup(pod *v1.Pod, group podGroup, now time.Time) *framework.Status {
+	members := e.waitingGroupMembers(group, pod.UID)
+
+	placed := 1 + len(members) + e.boundGroupMembers(group)
+	if placed < group.size {
+		return framework.NewStatus(framework.Wait, fmt.Sprintf("%d of %d pods of group %s placed", placed, group.size, group.name))
+	}
+
+	for _, member := range members {
+		if status := e.permitDecision(member.GetPod(), now); !status.IsSuccess() {
+			return framework.NewStatus(framework.Wait, fmt.Sprintf("pod %s of group %s deferred: %s", member.GetPod().Name, group.name, status.Message()))
+		}
+	}
+
+	for _, member := range members {
+		member.Allow(Name)
+	}
+
+	return nil
+}
+
+// waitingGroupMembers returns the other pods of the group held at Permit by
+// this plugin.
+func (e *Emissions) waitingGroupMembers(group podGroup, self types.UID) []framework.WaitingPod {
+	var members []framework.WaitingPod
+
+	e.handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		pod := waitingPod.GetPod()
+
+		if pod.UID != self && group.has(pod) && isPendingOn(waitingPod, Name) {
+			members = append(members, waitingPod)
+		}
+	})
+
+	return members
+}
+
+// boundGroupMembers returns the number of running pods of the group, such as
+// members admitted before one of them was recreated.
+func (e *Emissions) boundGroupMembers(group podGroup) int {
+	pods, err := e.podLister.Pods(group.namespace).List(labels.Everything())
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		if group.has(pod) {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,165 @@
+package emissions
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// inGroup adds the pod to the named group of the given size.
+func inGroup(pod *v1.Pod, group, size string) *v1.Pod {
+	pod.Annotations[groupAnnotation] = group
+	pod.Annotations[groupSizeAnnotation] = size
+
+	return pod
+}
+
+// bound marks the pod as running on a node.
+func bound(pod *v1.Pod) *v1.Pod {
+	pod.Spec.NodeName = "node"
+	pod.Status.Phase = v1.PodRunning
+
+	return pod
+}
+
+func TestPodGroupOf(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		pod    *v1.Pod
+		want   podGroup
+		wantOK bool
+	}{
+		{
+			name: "no group",
+			pod:  testPod("pod", 50, now),
+		},
+		{
+			name:   "group",
+			pod:    inGroup(testPod("pod", 50, now), "train", "3"),
+			want:   podGroup{namespace: "default", name: "train", size: 3},
+			wantOK: true,
+		},
+		{
+			name: "empty group name",
+			pod:  inGroup(testPod("pod", 50, now), "", "3"),
+		},
+		{
+			name: "invalid size",
+			pod:  inGroup(testPod("pod", 50, now), "train", "three"),
+		},
+		{
+			name: "zero size",
+			pod:  inGroup(testPod("pod", 50, now), "train", "0"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := podGroupOf(tt.pod)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("podGroupOf() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAdmitGroup(t *testing.T) {
+	now := time.Now()
+
+	otherNamespace := inGroup(testPod("other-namespace", 50, now), "train", "3")
+	otherNamespace.Namespace = "other"
+
+	finished := bound(inGroup(testPod("finished", 50, now), "train", "3"))
+	finished.Status.Phase = v1.PodSucceeded
+
+	tests := []struct {
+		name        string
+		index       int
+		waitingPods []*fakeWaitingPod
+		pods        []*v1.Pod
+		want        framework.Code
+	}{
+		{
+			name:  "whole group waiting",
+			index: 40,
+			waitingPods: []*fakeWaitingPod{
+				waiting(inGroup(testPod("b", 50, now), "train", "3")),
+				waiting(inGroup(testPod("c", 50, now), "train", "3")),
+			},
+			want: framework.Success,
+		},
+		{
+			name:  "part of the group placed",
+			index: 40,
+			waitingPods: []*fakeWaitingPod{
+				waiting(inGroup(testPod("b", 50, now), "train", "3")),
+			},
+			want: framework.Wait,
+		},
+		{
+			name:  "running members count as placed",
+			index: 40,
+			waitingPods: []*fakeWaitingPod{
+				waiting(inGroup(testPod("b", 50, now), "train", "3")),
+			},
+			pods: []*v1.Pod{bound(inGroup(testPod("c", 50, now), "train", "3"))},
+			want: framework.Success,
+		},
+		{
+			name:  "finished members do not count",
+			index: 40,
+			waitingPods: []*fakeWaitingPod{
+				waiting(inGroup(testPod("b", 50, now), "train", "3")),
+			},
+			pods: []*v1.Pod{finished},
+			want: framework.Wait,
+		},
+		{
+			name:  "members of other groups do not count",
+			index: 40,
+			waitingPods: []*fakeWaitingPod{
+				waiting(inGroup(testPod("b", 50, now), "train", "3")),
+				waiting(inGroup(testPod("c", 50, now), "other", "3")),
+				waiting(otherNamespace),
+			},
+			want: framework.Wait,
+		},
+		{
+			name:  "member deferred",
+			index: 40,
+			waitingPods: []*fakeWaitingPod{
+				waiting(inGroup(testPod("b", 50, now), "train", "3")),
+				waiting(inGroup(testPod("c", 30, now), "train", "3")),
+			},
+			want: framework.Wait,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEmissions(t, &config.EmissionsArgs{}, tt.index, tt.waitingPods, tt.pods)
+
+			pod := inGroup(testPod("a", 50, now), "train", "3")
+			group, _ := podGroupOf(pod)
+
+			status := e.admitGroup(pod, group, now)
+			if got := status.Code(); got != tt.want {
+				t.Fatalf("admitGroup() = %v (%s), want %v", got, status.Message(), tt.want)
+			}
+
+			for _, waitingPod := range tt.waitingPods {
+				wantAllowed := tt.want == framework.Success && group.has(waitingPod.pod)
+
+				if waitingPod.allowed != wantAllowed {
+					t.Errorf("pod %s allowed = %v, want %v", waitingPod.pod.Name, waitingPod.allowed, wantAllowed)
+				}
+			}
+		})
+	}
+}
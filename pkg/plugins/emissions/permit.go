@@ -0,0 +1,161 @@
+package emissions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+)
+
+// maxWaitAnnotation sets how long after its creation a pod may be held
+// because of the index, as a duration such as "6h".
+const maxWaitAnnotation = "carbon.siderolabs.com/max-wait"
+
+// maxPermitWait is the longest the framework lets a pod wait at Permit in a
+// single scheduling attempt.
+const maxPermitWait = 15 * time.Minute
+
+// waitingPodsReviewInterval is how often pods held at Permit are checked for
+// an expired max wait.
+const waitingPodsReviewInterval = 10 * time.Second
+
+var _ = framework.PermitPlugin(&Emissions{})
+
+// Permit holds pods at Permit while the index is above their priority, when
+// DeferralMode is Permit, and holds members of a group until the whole group
+// is placed.
+func (e *Emissions) Permit(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
+	group, inGroup := podGroupOf(pod)
+
+	if e.args.Args().DeferralMode != config.DeferralModePermit && !inGroup {
+		return nil, 0
+	}
+
+	status := e.permitDecision(pod, time.Now())
+
+	switch {
+	case status.IsWait():
+		if err := e.setDeferredCondition(ctx, pod, v1.ConditionTrue, reasonIndexAbovePriority, status.Message()); err != nil {
+			log.Printf("failed to update condition of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	case status.IsSuccess() && inGroup:
+		status = e.admitGroup(pod, group, time.Now())
+	}
+
+	if !status.IsWait() {
+		return status, 0
+	}
+
+	timeout := maxPermitWait
+
+	// Give the review loop a chance to apply the policy before the framework
+	// rejects the pod on timeout.
+	if deadline, ok := maxWaitDeadline(pod); ok {
+		if remaining := time.Until(deadline) + waitingPodsReviewInterval; remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	return status, timeout
+}
+
+// permitDecision returns Success to admit the pod, Wait to keep holding it,
+// or Unschedulable to reject it.
+func (e *Emissions) permitDecision(pod *v1.Pod, now time.Time) *framework.Status {
+	if pod.Spec.Priority == nil {
+		return nil
+	}
+
+	args := e.args.Args()
+
+	index, err := e.indexCache.Index()
+	if err == nil && energy.Allows(*pod.Spec.Priority, index) {
+		return nil
+	}
+
+	if err != nil && args.FailurePolicy == config.FailurePolicyAllow {
+		return nil
+	}
+
+	if maxWaitExpired(pod, now) {
+		if args.MaxWaitPolicy == config.MaxWaitPolicyReject {
+			return framework.NewStatus(framework.Unschedulable, "max wait exceeded while index is above pod priority")
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return framework.NewStatus(framework.Wait, fmt.Sprintf("failed to get index: %v", err))
+	}
+
+	return framework.NewStatus(framework.Wait, fmt.Sprintf("pod priority (%d) lower than index (%d)", *pod.Spec.Priority, index))
+}
+
+// reviewWaitingPods admits or rejects the pods held at Permit after the index
+// changed or their max wait passed.
+func (e *Emissions) reviewWaitingPods() {
+	now := time.Now()
+
+	// Collect the pods first, as admitting a group iterates over them again.
+	var waitingPods []framework.WaitingPod
+
+	e.handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		if isPendingOn(waitingPod, Name) {
+			waitingPods = append(waitingPods, waitingPod)
+		}
+	})
+
+	for _, waitingPod := range waitingPods {
+		pod := waitingPod.GetPod()
+
+		status := e.permitDecision(pod, now)
+		if group, ok := podGroupOf(pod); ok && status.IsSuccess() {
+			status = e.admitGroup(pod, group, now)
+		}
+
+		switch {
+		case status.IsSuccess():
+			waitingPod.Allow(Name)
+		case status.IsUnschedulable():
+			waitingPod.Reject(Name, status.Message())
+		}
+	}
+}
+
+func isPendingOn(waitingPod framework.WaitingPod, plugin string) bool {
+	for _, name := range waitingPod.GetPendingPlugins() {
+		if name == plugin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxWaitDeadline returns the time until which the pod may be held.
+func maxWaitDeadline(pod *v1.Pod) (time.Time, bool) {
+	value, ok := pod.Annotations[maxWaitAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	maxWait, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return pod.CreationTimestamp.Add(maxWait), true
+}
+
+func maxWaitExpired(pod *v1.Pod, now time.Time) bool {
+	deadline, ok := maxWaitDeadline(pod)
+
+	return ok && !now.Before(deadline)
+}
@@ -0,0 +1,271 @@
+package emissions
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/dynamicconfig"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+)
+
+// noIndex makes newTestEmissions use a cache without an index, as when the
+// provider fails.
+const noIndex = -1
+
+type fixedIndex int
+
+func (i fixedIndex) Index() (int, error) {
+	return int(i), nil
+}
+
+// newTestCache returns a cache holding the index, or none when the index is
+// noIndex.
+func newTestCache(t *testing.T, index int) *energy.Cache {
+	t.Helper()
+
+	indexCache := energy.NewCache(fixedIndex(index), time.Minute)
+	if index == noIndex {
+		return indexCache
+	}
+
+	refreshed := make(chan struct{})
+	indexCache.OnChange(func(int) { close(refreshed) })
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+
+	indexCache.Run(stopCh)
+
+	select {
+	case <-refreshed:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("index cache was not refreshed")
+	}
+
+	return indexCache
+}
+
+// fakeHandle is a framework.Handle that only knows about waiting pods.
+type fakeHandle struct {
+	framework.Handle
+
+	waitingPods []*fakeWaitingPod
+}
+
+func (h *fakeHandle) IterateOverWaitingPods(callback func(framework.WaitingPod)) {
+	for _, waitingPod := range h.waitingPods {
+		callback(waitingPod)
+	}
+}
+
+// fakeWaitingPod records whether it was allowed or rejected.
+type fakeWaitingPod struct {
+	pod            *v1.Pod
+	pendingPlugins []string
+	allowed        bool
+	rejected       bool
+}
+
+func (w *fakeWaitingPod) GetPod() *v1.Pod {
+	return w.pod
+}
+
+func (w *fakeWaitingPod) GetPendingPlugins() []string {
+	return w.pendingPlugins
+}
+
+func (w *fakeWaitingPod) Allow(pluginName string) {
+	w.allowed = true
+}
+
+func (w *fakeWaitingPod) Reject(pluginName, msg string) {
+	w.rejected = true
+}
+
+func waiting(pod *v1.Pod) *fakeWaitingPod {
+	return &fakeWaitingPod{pod: pod, pendingPlugins: []string{Name}}
+}
+
+// newTestEmissions returns a plugin with the given index, pods waiting at
+// Permit and pods known to the pod lister.
+func newTestEmissions(t *testing.T, args *config.EmissionsArgs, index int, waitingPods []*fakeWaitingPod, pods []*v1.Pod) *Emissions {
+	t.Helper()
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+	for _, pod := range pods {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatalf("failed to add pod %q: %v", pod.Name, err)
+		}
+	}
+
+	return &Emissions{
+		handle:     &fakeHandle{waitingPods: waitingPods},
+		args:       dynamicconfig.NewSource(args),
+		indexCache: newTestCache(t, index),
+		podLister:  corelisters.NewPodLister(indexer),
+	}
+}
+
+// testPod returns a pod of the given priority created at created.
+func testPod(name string, priority int32, created time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       map[string]string{},
+		},
+		Spec: v1.PodSpec{Priority: &priority},
+	}
+}
+
+func withMaxWait(pod *v1.Pod, maxWait string) *v1.Pod {
+	pod.Annotations[maxWaitAnnotation] = maxWait
+
+	return pod
+}
+
+func TestPermitDecision(t *testing.T) {
+	now := time.Now()
+
+	noPriority := testPod("pod", 0, now)
+	noPriority.Spec.Priority = nil
+
+	tests := []struct {
+		name          string
+		index         int
+		failurePolicy string
+		maxWaitPolicy string
+		pod           *v1.Pod
+		want          framework.Code
+	}{
+		{
+			name:  "no priority",
+			index: 90,
+			pod:   noPriority,
+			want:  framework.Success,
+		},
+		{
+			name:  "index below priority",
+			index: 40,
+			pod:   testPod("pod", 50, now),
+			want:  framework.Success,
+		},
+		{
+			name:  "index at priority",
+			index: 50,
+			pod:   testPod("pod", 50, now),
+			want:  framework.Wait,
+		},
+		{
+			name:  "index above priority",
+			index: 60,
+			pod:   testPod("pod", 50, now),
+			want:  framework.Wait,
+		},
+		{
+			name:  "max wait not expired",
+			index: 60,
+			pod:   withMaxWait(testPod("pod", 50, now.Add(-time.Hour)), "2h"),
+			want:  framework.Wait,
+		},
+		{
+			name:          "max wait expired, allow",
+			index:         60,
+			maxWaitPolicy: config.MaxWaitPolicyAllow,
+			pod:           withMaxWait(testPod("pod", 50, now.Add(-3*time.Hour)), "2h"),
+			want:          framework.Success,
+		},
+		{
+			name:          "max wait expired, reject",
+			index:         60,
+			maxWaitPolicy: config.MaxWaitPolicyReject,
+			pod:           withMaxWait(testPod("pod", 50, now.Add(-3*time.Hour)), "2h"),
+			want:          framework.Unschedulable,
+		},
+		{
+			name:  "invalid max wait",
+			index: 60,
+			pod:   withMaxWait(testPod("pod", 50, now.Add(-3*time.Hour)), "soon"),
+			want:  framework.Wait,
+		},
+		{
+			name:          "index unavailable, defer",
+			index:         noIndex,
+			failurePolicy: config.FailurePolicyDefer,
+			pod:           testPod("pod", 50, now),
+			want:          framework.Wait,
+		},
+		{
+			name:          "index unavailable, allow",
+			index:         noIndex,
+			failurePolicy: config.FailurePolicyAllow,
+			pod:           testPod("pod", 50, now),
+			want:          framework.Success,
+		},
+		{
+			name:          "index unavailable, max wait expired",
+			index:         noIndex,
+			failurePolicy: config.FailurePolicyDefer,
+			maxWaitPolicy: config.MaxWaitPolicyReject,
+			pod:           withMaxWait(testPod("pod", 50, now.Add(-3*time.Hour)), "2h"),
+			want:          framework.Unschedulable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEmissions(t, &config.EmissionsArgs{
+				FailurePolicy: tt.failurePolicy,
+				MaxWaitPolicy: tt.maxWaitPolicy,
+			}, tt.index, nil, nil)
+
+			if got := e.permitDecision(tt.pod, now).Code(); got != tt.want {
+				t.Errorf("permitDecision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewWaitingPods(t *testing.T) {
+	now := time.Now()
+
+	admitted := waiting(testPod("admitted", 50, now))
+	deferred := waiting(testPod("deferred", 30, now))
+	expired := waiting(withMaxWait(testPod("expired", 30, now.Add(-3*time.Hour)), "2h"))
+
+	otherPlugin := waiting(testPod("other-plugin", 50, now))
+	otherPlugin.pendingPlugins = []string{"Other"}
+
+	e := newTestEmissions(t, &config.EmissionsArgs{
+		MaxWaitPolicy: config.MaxWaitPolicyReject,
+	}, 40, []*fakeWaitingPod{admitted, deferred, expired, otherPlugin}, nil)
+
+	e.reviewWaitingPods()
+
+	for _, tt := range []struct {
+		waitingPod   *fakeWaitingPod
+		wantAllowed  bool
+		wantRejected bool
+	}{
+		{waitingPod: admitted, wantAllowed: true},
+		{waitingPod: deferred},
+		{waitingPod: expired, wantRejected: true},
+		{waitingPod: otherPlugin},
+	} {
+		if tt.waitingPod.allowed != tt.wantAllowed || tt.waitingPod.rejected != tt.wantRejected {
+			t.Errorf("pod %s allowed = %v, rejected = %v, want %v, %v", tt.waitingPod.pod.Name,
+				tt.waitingPod.allowed, tt.waitingPod.rejected, tt.wantAllowed, tt.wantRejected)
+		}
+	}
+}
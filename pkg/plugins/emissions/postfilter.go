@@ -0,0 +1,87 @@
+package emissions
+
+import (
+	"context"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+)
+
+// CarbonDeferred is the condition set on pods held back because the index is
+// above their priority.
+const CarbonDeferred v1.PodConditionType = "CarbonDeferred"
+
+// Reasons of the CarbonDeferred condition.
+const (
+	reasonIndexAbovePriority = "IndexAbovePriority"
+	reasonIndexBelowPriority = "IndexBelowPriority"
+)
+
+// deferredStateKey is the CycleState key PreFilter uses to tell PostFilter
+// that the pod was deferred.
+const deferredStateKey framework.StateKey = Name + "/deferred"
+
+type deferredState struct {
+	message string
+}
+
+// Clone returns the state itself, as it is never modified.
+func (s *deferredState) Clone() framework.StateData {
+	return s
+}
+
+var _ = framework.PostFilterPlugin(&Emissions{})
+var _ = framework.PreBindPlugin(&Emissions{})
+
+// PostFilter marks pods deferred by PreFilter with the CarbonDeferred
+// condition and stops later PostFilter plugins, as preempting other pods
+// cannot make the index drop. Other pods may preempt, preferring flexible
+// victims.
+func (e *Emissions) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	data, err := state.Read(deferredStateKey)
+	if err != nil {
+		return e.preempt(ctx, state, pod, filteredNodeStatusMap)
+	}
+
+	deferred := data.(*deferredState)
+
+	if err = e.setDeferredCondition(ctx, pod, v1.ConditionTrue, reasonIndexAbovePriority, deferred.message); err != nil {
+		log.Printf("failed to update condition of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+
+	return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, deferred.message)
+}
+
+// PreBind clears the CarbonDeferred condition of pods that were deferred
+// before.
+func (e *Emissions) PreBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if _, condition := podutil.GetPodCondition(&pod.Status, CarbonDeferred); condition == nil || condition.Status != v1.ConditionTrue {
+		return nil
+	}
+
+	if err := e.setDeferredCondition(ctx, pod, v1.ConditionFalse, reasonIndexBelowPriority, ""); err != nil {
+		log.Printf("failed to update condition of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+
+	return nil
+}
+
+func (e *Emissions) setDeferredCondition(ctx context.Context, pod *v1.Pod, status v1.ConditionStatus, reason, message string) error {
+	newStatus := pod.Status.DeepCopy()
+
+	if !podutil.UpdatePodCondition(newStatus, &v1.PodCondition{
+		Type:               CarbonDeferred,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}) {
+		return nil
+	}
+
+	return schedutil.PatchPodStatus(ctx, e.handle.ClientSet(), pod, newStatus)
+}
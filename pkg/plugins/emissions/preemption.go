@@ -0,0 +1,111 @@
+package emissions
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/features"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/controllers/pod"
+)
+
+// flexibleAnnotation marks pods that tolerate being preempted and run again
+// later, once the index allows. They are preferred as preemption victims.
+const flexibleAnnotation = "carbon.siderolabs.com/flexible"
+
+// carbonPreemption is the preemption.Interface of DefaultPreemption, changed
+// to prefer nodes whose victims are flexible. Victims are still selected on
+// each node by DefaultPreemption.
+type carbonPreemption struct {
+	*defaultpreemption.DefaultPreemption
+
+	podManager *pod.PodManager
+}
+
+var _ = preemption.Interface(&carbonPreemption{})
+
+// preempt runs preemption preferring flexible victims. The controllers
+// account for the victims as evictions once the scheduler marks them.
+func (e *Emissions) preempt(ctx context.Context, state *framework.CycleState, pod *v1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	p := &carbonPreemption{
+		DefaultPreemption: e.defaultPreemption,
+		podManager:        e.controllers.PodManager(),
+	}
+
+	evaluator := preemption.Evaluator{
+		PluginName: Name,
+		Handler:    e.handle,
+		PodLister:  e.podLister,
+		PdbLister:  e.pdbLister,
+		State:      state,
+		Interface:  p,
+	}
+
+	return evaluator.Preempt(ctx, pod, filteredNodeStatusMap)
+}
+
+// isFlexible reports whether the pod is flexible and may be evicted within
+// the eviction limits. The limits are only known to the replica running the
+// controllers, so pods are not taken as flexible elsewhere.
+func (p *carbonPreemption) isFlexible(pod *v1.Pod) bool {
+	if pod.Annotations[flexibleAnnotation] != "true" {
+		return false
+	}
+
+	return p.podManager != nil && p.podManager.Evictable(pod)
+}
+
+// CandidatesToVictimsMap keeps only the candidates with the fewest victims
+// that are not flexible, leaving the choice among them to the default
+// criteria.
+func (p *carbonPreemption) CandidatesToVictimsMap(candidates []preemption.Candidate) map[string]*extenderv1.Victims {
+	victimsMap := p.DefaultPreemption.CandidatesToVictimsMap(candidates)
+
+	inflexible := make(map[string]int, len(victimsMap))
+	fewest := -1
+
+	for node, victims := range victimsMap {
+		for _, victim := range victims.Pods {
+			if !p.isFlexible(victim) {
+				inflexible[node]++
+			}
+		}
+
+		if fewest == -1 || inflexible[node] < fewest {
+			fewest = inflexible[node]
+		}
+	}
+
+	for node := range victimsMap {
+		if inflexible[node] > fewest {
+			delete(victimsMap, node)
+		}
+	}
+
+	return victimsMap
+}
+
+// newDefaultPreemption creates the DefaultPreemption the plugin preempts
+// with, configured from args as DefaultPreemption is disabled in its favor.
+func newDefaultPreemption(h framework.Handle, args *config.EmissionsArgs) (*defaultpreemption.DefaultPreemption, error) {
+	plugin, err := defaultpreemption.New(&schedulerconfig.DefaultPreemptionArgs{
+		MinCandidateNodesPercentage: args.MinCandidateNodesPercentage,
+		MinCandidateNodesAbsolute:   args.MinCandidateNodesAbsolute,
+	}, h, feature.Features{
+		EnablePodDisruptionConditions: utilfeature.DefaultFeatureGate.Enabled(features.PodDisruptionConditions),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create default preemption: %w", err)
+	}
+
+	return plugin.(*defaultpreemption.DefaultPreemption), nil
+}
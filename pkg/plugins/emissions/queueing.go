@@ -0,0 +1,49 @@
+package emissions
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+)
+
+var _ = framework.EnqueueExtensions(&Emissions{})
+
+// EventsToRegister returns the events that may make a pod rejected by the
+// plugin schedulable: the published index of the profile's provider region
+// dropping below its priority.
+func (e *Emissions) EventsToRegister() []framework.ClusterEventWithHint {
+	return []framework.ClusterEventWithHint{
+		{
+			Event:          framework.ClusterEvent{Resource: energy.IndexEventResource, ActionType: framework.Add | framework.Update},
+			QueueingHintFn: e.isSchedulableAfterIndexChange,
+		},
+	}
+}
+
+func (e *Emissions) isSchedulableAfterIndexChange(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) framework.QueueingHint {
+	if pod.Spec.Priority == nil {
+		return framework.QueueSkip
+	}
+
+	// The published index is used rather than the profile's cache, which may
+	// not have been refreshed yet, as the event would not come again.
+	provider, region, index, ok := energy.PublishedIndex(newObj)
+	if !ok {
+		return framework.QueueSkip
+	}
+
+	args := e.args.Args()
+	if provider != args.Provider || region != args.WattTimeBA {
+		return framework.QueueSkip
+	}
+
+	if !energy.Allows(*pod.Spec.Priority, index) {
+		logger.V(5).Info("index still above pod priority", "pod", klog.KObj(pod), "priority", *pod.Spec.Priority, "index", index)
+
+		return framework.QueueSkip
+	}
+
+	return framework.QueueImmediately
+}
@@ -0,0 +1,32 @@
+package emissions
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/siderolabs/kube-scheduler/pkg/power"
+)
+
+var _ = framework.ScorePlugin(&Emissions{})
+
+// Score prefers nodes that are not power managed, so that power managed nodes
+// are left idle and can be powered off.
+func (e *Emissions) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	nodeInfo, err := e.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("failed to get node %q from snapshot: %w", nodeName, err))
+	}
+
+	if power.IsManaged(nodeInfo.Node()) {
+		return framework.MinNodeScore, nil
+	}
+
+	return framework.MaxNodeScore, nil
+}
+
+func (e *Emissions) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
@@ -0,0 +1,74 @@
+package emissions
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+	"github.com/siderolabs/kube-scheduler/pkg/energy"
+	"github.com/siderolabs/kube-scheduler/pkg/energy/watttime"
+)
+
+// providerKey identifies the provider settings of a profile. Profiles with
+// the same settings share a cache, and so a single provider login. Inline
+// credentials are keyed by their hash, so as not to keep them around.
+type providerKey struct {
+	provider          string
+	region            string
+	credentials       [sha256.Size]byte
+	credentialsSecret string
+	credentialsFile   string
+	refreshInterval   time.Duration
+}
+
+func providerKeyOf(args *config.EmissionsArgs) providerKey {
+	key := providerKey{
+		provider:        args.Provider,
+		region:          args.WattTimeBA,
+		credentialsFile: args.CredentialsFile,
+		refreshInterval: args.IndexRefreshInterval.Duration,
+	}
+
+	if args.WattTimeUsername != "" || args.WattTimePassword != "" {
+		key.credentials = sha256.Sum256([]byte(args.WattTimeUsername + "\x00" + args.WattTimePassword))
+	}
+
+	if ref := args.CredentialsSecretRef; ref != nil {
+		key.credentialsSecret = ref.Namespace + "/" + ref.Name
+	}
+
+	return key
+}
+
+var (
+	sharedCachesMu sync.Mutex
+	sharedCaches   = map[providerKey]*energy.Cache{}
+)
+
+// sharedCache returns the running cache for the provider settings of args,
+// creating it on first use. It reports whether the cache was created by this
+// call, the caller then owning its tunable settings.
+func sharedCache(args *config.EmissionsArgs, clientset kubernetes.Interface, stopCh <-chan struct{}) (*energy.Cache, bool, error) {
+	key := providerKeyOf(args)
+
+	sharedCachesMu.Lock()
+	defer sharedCachesMu.Unlock()
+
+	if indexCache, ok := sharedCaches[key]; ok {
+		return indexCache, false, nil
+	}
+
+	indexCache, err := watttime.NewCache(args, clientset, stopCh)
+	if err != nil {
+		return nil, false, err
+	}
+
+	indexCache.Run(stopCh)
+
+	sharedCaches[key] = indexCache
+
+	return indexCache, true, nil
+}
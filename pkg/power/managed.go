@@ -0,0 +1,23 @@
+package power
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// Annotations identifying the power driver of a node.
+const (
+	BMCEndpointAnnotation = "bmc.siderolabs.com/endpoint"
+	WOLMACAnnotation      = "wol.siderolabs.com/mac"
+)
+
+// IsManaged reports whether the node carries annotations for any of the
+// supported power drivers.
+func IsManaged(node *v1.Node) bool {
+	if _, ok := node.Annotations[BMCEndpointAnnotation]; ok {
+		return true
+	}
+
+	_, ok := node.Annotations[WOLMACAnnotation]
+
+	return ok
+}
@@ -0,0 +1,13 @@
+package power
+
+// Driver controls the power state of a single machine.
+type Driver interface {
+	// PowerOn will power on the machine.
+	PowerOn() error
+	// PowerOff will power off the machine.
+	PowerOff() error
+	// IsPoweredOn checks current power state.
+	IsPoweredOn() (bool, error)
+	// Close releases any resources held by the driver.
+	Close() error
+}
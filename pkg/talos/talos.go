@@ -0,0 +1,168 @@
+package talos
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"sigs.k8s.io/yaml"
+)
+
+// APIPort is the port apid listens on.
+const APIPort = "50000"
+
+// Config is the subset of a talosconfig needed to authenticate to the Talos
+// API.
+type Config struct {
+	Context  string              `json:"context"`
+	Contexts map[string]*Context `json:"contexts"`
+}
+
+// Context holds the credentials of a single talosconfig context.
+type Context struct {
+	Endpoints []string `json:"endpoints,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	CA        string   `json:"ca"`
+	Crt       string   `json:"crt"`
+	Key       string   `json:"key"`
+}
+
+// ParseConfig parses a talosconfig.
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := &Config{}
+
+	err := yaml.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse talosconfig: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// CurrentContext returns the active context.
+func (c *Config) CurrentContext() (*Context, error) {
+	ctx, ok := c.Contexts[c.Context]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found in talosconfig", c.Context)
+	}
+
+	return ctx, nil
+}
+
+// Client talks to the machine API of a single Talos node.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient connects to the Talos API on the given node address using the
+// credentials of the active talosconfig context.
+func NewClient(cfg *Config, address string) (*Client, error) {
+	talosContext, err := cfg.CurrentContext()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := talosContext.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(net.JoinHostPort(address, APIPort), grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial Talos API: %w", err)
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// Close the client.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Shutdown asks machined to gracefully shut the node down.
+func (c *Client) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// An empty ShutdownRequest has no fields set, i.e. a graceful
+	// (non-forced) shutdown, and encodes to zero bytes.
+	var res rawMessage
+
+	err := c.conn.Invoke(ctx, "/machine.MachineService/Shutdown", rawMessage{}, &res, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return fmt.Errorf("shutdown request failed: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Context) tlsConfig() (*tls.Config, error) {
+	ca, err := base64.StdEncoding.DecodeString(c.CA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode CA: %w", err)
+	}
+
+	crt, err := base64.StdEncoding.DecodeString(c.Crt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode certificate: %w", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(c.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key: %w", err)
+	}
+
+	cert, err := tls.X509KeyPair(crt, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("failed to load CA")
+	}
+
+	return &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// rawMessage is an already encoded protobuf message.
+type rawMessage []byte
+
+// rawCodec passes protobuf messages through without decoding them, so that
+// requests with no fields set can be sent without the generated API types.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(rawMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+
+	return msg, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*rawMessage)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+
+	*msg = append((*msg)[:0], data...)
+
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
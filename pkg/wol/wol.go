@@ -0,0 +1,71 @@
+package wol
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+// Client sends Wake-on-LAN magic packets to a single machine.
+type Client struct {
+	// MAC is the hardware address of the interface to wake.
+	MAC net.HardwareAddr
+	// BroadcastAddress is the UDP address the magic packet is sent to.
+	BroadcastAddress string
+}
+
+// NewClient creates a Wake-on-LAN client for the given MAC address.
+func NewClient(mac, broadcastAddress string) (*Client, error) {
+	hwAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAC address %q: %w", mac, err)
+	}
+
+	if len(hwAddr) != 6 {
+		return nil, fmt.Errorf("invalid MAC address %q: expected 6 bytes", mac)
+	}
+
+	// The args are defaulted already, unless the client is created otherwise.
+	if broadcastAddress == "" {
+		broadcastAddress = config.DefaultWakeOnLANBroadcastAddress
+	}
+
+	return &Client{MAC: hwAddr, BroadcastAddress: broadcastAddress}, nil
+}
+
+// Wake sends a magic packet to the machine.
+func (c *Client) Wake() error {
+	addr, err := net.ResolveUDPAddr("udp", c.BroadcastAddress)
+	if err != nil {
+		return fmt.Errorf("failed to resolve broadcast address: %w", err)
+	}
+
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return fmt.Errorf("failed to dial broadcast address: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write(MagicPacket(c.MAC))
+	if err != nil {
+		return fmt.Errorf("failed to send magic packet: %w", err)
+	}
+
+	return nil
+}
+
+// MagicPacket builds the magic packet for a MAC address: six 0xff bytes
+// followed by the address repeated sixteen times.
+func MagicPacket(mac net.HardwareAddr) []byte {
+	var buf bytes.Buffer
+
+	buf.Write(bytes.Repeat([]byte{0xff}, 6))
+
+	for i := 0; i < 16; i++ {
+		buf.Write(mac)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,82 @@
+package wol
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/siderolabs/kube-scheduler/apis/config"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name             string
+		mac              string
+		broadcastAddress string
+		wantBroadcast    string
+		wantErr          bool
+	}{
+		{
+			name:             "configured broadcast address",
+			mac:              "00:11:22:33:44:55",
+			broadcastAddress: "192.168.1.255:7",
+			wantBroadcast:    "192.168.1.255:7",
+		},
+		{
+			name:          "default broadcast address",
+			mac:           "00:11:22:33:44:55",
+			wantBroadcast: config.DefaultWakeOnLANBroadcastAddress,
+		},
+		{
+			name:    "invalid MAC address",
+			mac:     "not-a-mac",
+			wantErr: true,
+		},
+		{
+			name:    "EUI-64 MAC address",
+			mac:     "00:11:22:33:44:55:66:77",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.mac, tt.broadcastAddress)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewClient() succeeded, want error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewClient() failed: %v", err)
+			}
+
+			if client.BroadcastAddress != tt.wantBroadcast {
+				t.Errorf("BroadcastAddress = %q, want %q", client.BroadcastAddress, tt.wantBroadcast)
+			}
+		})
+	}
+}
+
+func TestMagicPacket(t *testing.T) {
+	mac := net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+
+	packet := MagicPacket(mac)
+
+	if len(packet) != 6+16*len(mac) {
+		t.Fatalf("len(packet) = %d, want %d", len(packet), 6+16*len(mac))
+	}
+
+	if !bytes.Equal(packet[:6], bytes.Repeat([]byte{0xff}, 6)) {
+		t.Errorf("packet starts with %x, want six 0xff bytes", packet[:6])
+	}
+
+	for i := 0; i < 16; i++ {
+		if got := packet[6+i*len(mac) : 6+(i+1)*len(mac)]; !bytes.Equal(got, mac) {
+			t.Errorf("repetition %d = %x, want %x", i, got, []byte(mac))
+		}
+	}
+}